@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+// pullRequestHost returns the host pri lives on, taken from its URL.
+func pullRequestHost(pri *PullRequestItem) string {
+	u, err := url.Parse(pri.Url)
+	if err != nil {
+		return ""
+	}
+	return u.Host
+}
+
+// restRequest sends a request to the REST API of host, or of the default
+// host of gh when host is empty.
+func restRequest(host string, method string, path string, body interface{}) error {
+	client, err := api.NewRESTClient(api.ClientOptions{Host: host})
+	if err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	return client.Do(method, path, bytes.NewReader(payload), nil)
+}
+
+func addLabels(pri *PullRequestItem, labels []string) error {
+	path := fmt.Sprintf("repos/%s/issues/%d/labels", pri.RepositoryName, pri.Number)
+	return restRequest(pullRequestHost(pri), "POST", path, map[string][]string{"labels": labels})
+}
+
+// requestReviewers requests reviews from users and teams. Reviewers written
+// as "org/team-slug" are requested as teams.
+func requestReviewers(pri *PullRequestItem, reviewers []string) error {
+	users := []string{}
+	teams := []string{}
+	for _, reviewer := range reviewers {
+		if _, slug, ok := strings.Cut(reviewer, "/"); ok {
+			teams = append(teams, slug)
+		} else {
+			users = append(users, reviewer)
+		}
+	}
+
+	path := fmt.Sprintf("repos/%s/pulls/%d/requested_reviewers", pri.RepositoryName, pri.Number)
+	return restRequest(pullRequestHost(pri), "POST", path, map[string][]string{"reviewers": users, "team_reviewers": teams})
+}
+
+func closePullRequest(pri *PullRequestItem) error {
+	path := fmt.Sprintf("repos/%s/pulls/%d", pri.RepositoryName, pri.Number)
+	return restRequest(pullRequestHost(pri), "PATCH", path, map[string]string{"state": "closed"})
+}
+
+// splitValues splits a comma separated prompt input into trimmed,
+// non-empty values.
+func splitValues(s string) []string {
+	values := []string{}
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
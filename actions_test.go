@@ -0,0 +1,61 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitValues(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{
+			name:  "single value",
+			input: "bug",
+			want:  []string{"bug"},
+		},
+		{
+			name:  "comma separated values",
+			input: "bug, release ,urgent",
+			want:  []string{"bug", "release", "urgent"},
+		},
+		{
+			name:  "empty values are skipped",
+			input: " , bug,,",
+			want:  []string{"bug"},
+		},
+		{
+			name:  "empty input",
+			input: "",
+			want:  []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := splitValues(tt.input)
+			if !reflect.DeepEqual(result, tt.want) {
+				t.Errorf("splitValues(%q) = %v, want %v", tt.input, result, tt.want)
+			}
+		})
+	}
+}
+
+func TestPullRequestHost(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{"https://github.com/test/repo/pull/1", "github.com"},
+		{"https://ghe.corp.example/platform/api/pull/2", "ghe.corp.example"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := pullRequestHost(&PullRequestItem{Url: tt.url}); got != tt.want {
+			t.Errorf("pullRequestHost(%q) = %q, want %q", tt.url, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomEntry struct {
+	Title      string         `xml:"title"`
+	ID         string         `xml:"id"`
+	Link       atomLink       `xml:"link"`
+	Published  string         `xml:"published"`
+	Updated    string         `xml:"updated"`
+	Author     atomAuthor     `xml:"author"`
+	Categories []atomCategory `xml:"category"`
+	Summary    string         `xml:"summary"`
+}
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Updated string      `xml:"updated"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+func atomSummary(pri *PullRequestItem) string {
+	parts := []string{pri.RepositoryName}
+	if pri.IsDraft {
+		parts = append(parts, "draft")
+	}
+	if pri.CheckStatus != checkStatusUnknown && pri.CheckStatus != "" {
+		parts = append(parts, "checks: "+strings.ToLower(pri.CheckStatus))
+	}
+	if pri.ReviewDecision != "" {
+		parts = append(parts, "review: "+strings.ToLower(pri.ReviewDecision))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// writeAtom writes an Atom feed with an entry per pull request, newest
+// update first. Entries are identified by URL, so feed readers pick up
+// new and updated pull requests.
+func writeAtom(w io.Writer, orgs []string, repositories []RepositoryItem, now time.Time) error {
+	feed := atomFeed{
+		Title: resultTitle(orgs),
+		ID:    "urn:gh-list-prs:" + strings.Join(orgs, ","),
+	}
+
+	updated := time.Time{}
+	items := []PullRequestItem{}
+	for _, repo := range repositories {
+		items = append(items, repo.PullRequestItems...)
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].UpdatedAt.After(items[j].UpdatedAt)
+	})
+
+	for _, pr := range items {
+		if pr.UpdatedAt.After(updated) {
+			updated = pr.UpdatedAt
+		}
+		feed.Entries = append(feed.Entries, atomEntry{
+			Title:      fmt.Sprintf("%s: %s", pullRequestReference(&pr), pr.Title),
+			ID:         pr.Url,
+			Link:       atomLink{Href: pr.Url},
+			Published:  pr.CreatedAt.UTC().Format(time.RFC3339),
+			Updated:    pr.UpdatedAt.UTC().Format(time.RFC3339),
+			Author:     atomAuthor{Name: pr.Author},
+			Categories: []atomCategory{{Term: pr.RepositoryName}},
+			Summary:    atomSummary(&pr),
+		})
+	}
+	if updated.IsZero() {
+		updated = now
+	}
+	feed.Updated = updated.UTC().Format(time.RFC3339)
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(feed); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+	"time"
+)
+
+func TestWriteAtom(t *testing.T) {
+	now := time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)
+	repositories := []RepositoryItem{
+		{
+			Name: "test/a",
+			PullRequestItems: []PullRequestItem{
+				{Number: 1, Title: "Old", Author: "alice", Url: "https://github.com/test/a/pull/1", RepositoryName: "test/a", CreatedAt: now.AddDate(0, 0, -5), UpdatedAt: now.AddDate(0, 0, -3), CheckStatus: checkStatusUnknown},
+			},
+		},
+		{
+			Name: "test/b",
+			PullRequestItems: []PullRequestItem{
+				{Number: 7, Title: "New & shiny", Author: "bob", Url: "https://github.com/test/b/pull/7", RepositoryName: "test/b", CreatedAt: now.AddDate(0, 0, -2), UpdatedAt: now.AddDate(0, 0, -1), IsDraft: true, CheckStatus: checkStatusFailure, ReviewDecision: reviewDecisionApproved},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := writeAtom(&buf, []string{"test"}, repositories, now); err != nil {
+		t.Fatalf("writeAtom() returned error: %v", err)
+	}
+
+	var feed atomFeed
+	if err := xml.Unmarshal(buf.Bytes(), &feed); err != nil {
+		t.Fatalf("writeAtom() wrote invalid XML: %v", err)
+	}
+
+	if feed.Title != "PRs in test" {
+		t.Errorf("Title = %q, want %q", feed.Title, "PRs in test")
+	}
+	if feed.Updated != "2024-12-30T00:00:00Z" {
+		t.Errorf("Updated = %q, want the latest update", feed.Updated)
+	}
+	if len(feed.Entries) != 2 {
+		t.Fatalf("len(Entries) = %d, want 2", len(feed.Entries))
+	}
+
+	entry := feed.Entries[0]
+	if entry.Title != "test/b#7: New & shiny" {
+		t.Errorf("Entries[0].Title = %q, want the most recently updated PR", entry.Title)
+	}
+	if entry.ID != "https://github.com/test/b/pull/7" || entry.Link.Href != entry.ID {
+		t.Errorf("Entries[0] ID = %q, Link = %q, want the PR URL", entry.ID, entry.Link.Href)
+	}
+	if entry.Published != "2024-12-29T00:00:00Z" || entry.Updated != "2024-12-30T00:00:00Z" {
+		t.Errorf("Entries[0] Published = %q, Updated = %q", entry.Published, entry.Updated)
+	}
+	if entry.Author.Name != "bob" {
+		t.Errorf("Entries[0].Author = %q, want bob", entry.Author.Name)
+	}
+	if entry.Summary != "test/b, draft, checks: failure, review: approved" {
+		t.Errorf("Entries[0].Summary = %q", entry.Summary)
+	}
+	if feed.Entries[1].Summary != "test/a" {
+		t.Errorf("Entries[1].Summary = %q, want %q", feed.Entries[1].Summary, "test/a")
+	}
+}
+
+func TestWriteAtomEmpty(t *testing.T) {
+	now := time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)
+
+	var buf bytes.Buffer
+	if err := writeAtom(&buf, []string{"a", "b"}, nil, now); err != nil {
+		t.Fatalf("writeAtom() returned error: %v", err)
+	}
+
+	var feed atomFeed
+	if err := xml.Unmarshal(buf.Bytes(), &feed); err != nil {
+		t.Fatalf("writeAtom() wrote invalid XML: %v", err)
+	}
+	if feed.Title != "PRs in 2 orgs" || feed.Updated != "2024-12-31T00:00:00Z" || len(feed.Entries) != 0 {
+		t.Errorf("feed = %+v", feed)
+	}
+}
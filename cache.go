@@ -0,0 +1,106 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// cacheEntry is a search result stored in the cache.
+type cacheEntry struct {
+	FetchedAt    time.Time        `json:"fetched_at"`
+	Repositories []RepositoryItem `json:"repositories"`
+	Total        int              `json:"total"`
+}
+
+// searchCache stores search results as a JSON file per search in dir. An
+// empty dir disables the cache.
+type searchCache struct {
+	dir string
+}
+
+func defaultCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "gh-list-prs")
+}
+
+// cacheKey identifies a search by its host, query string and limit.
+func cacheKey(host string, queryString string, limit int) string {
+	sum := sha256.Sum256([]byte(host + "\n" + queryString + "\n" + strconv.Itoa(limit)))
+	return hex.EncodeToString(sum[:])
+}
+
+func (c *searchCache) path(key string) string {
+	return filepath.Join(c.dir, key+".json")
+}
+
+func (c *searchCache) load(key string) (*cacheEntry, error) {
+	if c.dir == "" {
+		return nil, fs.ErrNotExist
+	}
+
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return nil, err
+	}
+	entry := &cacheEntry{}
+	if err := json.Unmarshal(data, entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// store writes the entry through a temporary file, so that concurrent runs
+// never read a partial entry.
+func (c *searchCache) store(key string, entry cacheEntry) error {
+	if c.dir == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(c.dir, 0o700); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(c.dir, key+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), c.path(key))
+}
+
+// lookup returns the cached result for key when it is younger than ttl, or
+// of any age when offline. ok is false when the search has to run.
+func (c *searchCache) lookup(key string, ttl time.Duration, offline bool, now time.Time) (result searchResult, ok bool, err error) {
+	entry, err := c.load(key)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) && offline {
+		return searchResult{}, false, fmt.Errorf("reading cache: %w", err)
+	}
+	if entry != nil && (offline || now.Sub(entry.FetchedAt) < ttl) {
+		return searchResult{repositories: entry.Repositories, total: entry.Total}, true, nil
+	}
+	if offline {
+		return searchResult{}, false, errors.New("no cached result for the search, run it once without --offline")
+	}
+	return searchResult{}, false, nil
+}
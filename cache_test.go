@@ -0,0 +1,109 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCacheKey(t *testing.T) {
+	key := cacheKey("", "is:open org:a", 50)
+	if key != cacheKey("", "is:open org:a", 50) {
+		t.Error("cacheKey() is not stable")
+	}
+	for _, other := range []string{
+		cacheKey("ghe.corp.example", "is:open org:a", 50),
+		cacheKey("", "is:open org:b", 50),
+		cacheKey("", "is:open org:a", 100),
+	} {
+		if other == key {
+			t.Errorf("cacheKey() = %q for different searches", key)
+		}
+	}
+}
+
+func TestSearchCacheLookup(t *testing.T) {
+	now := time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)
+	cached := []RepositoryItem{{Name: "org/cached", PullRequestItems: []PullRequestItem{{Number: 1}}}}
+
+	tests := []struct {
+		name      string
+		fetchedAt time.Time
+		noEntry   bool
+		ttl       time.Duration
+		offline   bool
+		wantOK    bool
+		wantErr   bool
+	}{
+		{name: "no ttl", fetchedAt: now.Add(-time.Second)},
+		{name: "fresh entry", fetchedAt: now.Add(-time.Minute), ttl: 5 * time.Minute, wantOK: true},
+		{name: "expired entry", fetchedAt: now.Add(-time.Hour), ttl: 5 * time.Minute},
+		{name: "no entry", noEntry: true, ttl: 5 * time.Minute},
+		{name: "offline", fetchedAt: now.Add(-24 * time.Hour), offline: true, wantOK: true},
+		{name: "offline without entry", noEntry: true, offline: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := &searchCache{dir: t.TempDir()}
+			if !tt.noEntry {
+				if err := cache.store("key", cacheEntry{FetchedAt: tt.fetchedAt, Repositories: cached, Total: 3}); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			got, ok, err := cache.lookup("key", tt.ttl, tt.offline, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("lookup() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if ok != tt.wantOK {
+				t.Errorf("lookup() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if ok && (len(got.repositories) != 1 || got.repositories[0].Name != "org/cached" || got.total != 3) {
+				t.Errorf("lookup() = %+v, want the cached result", got)
+			}
+		})
+	}
+}
+
+func TestSearchCacheStore(t *testing.T) {
+	now := time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)
+	cache := &searchCache{dir: filepath.Join(t.TempDir(), "gh-list-prs")}
+
+	for _, name := range []string{"org/old", "org/new"} {
+		entry := cacheEntry{FetchedAt: now, Repositories: []RepositoryItem{{Name: name}}}
+		if err := cache.store("key", entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	entry, err := cache.load("key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !entry.FetchedAt.Equal(now) || entry.Repositories[0].Name != "org/new" {
+		t.Errorf("load() = %+v, want the last stored entry", entry)
+	}
+
+	files, err := os.ReadDir(cache.dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 {
+		t.Errorf("cache dir has %d files, want 1", len(files))
+	}
+}
+
+func TestSearchCacheDisabled(t *testing.T) {
+	cache := &searchCache{}
+	if err := cache.store("key", cacheEntry{}); err != nil {
+		t.Errorf("store() error = %v", err)
+	}
+	if _, ok, err := cache.lookup("key", time.Hour, false, time.Now()); ok || err != nil {
+		t.Errorf("lookup() = %v, %v, want a miss", ok, err)
+	}
+	if _, _, err := cache.lookup("key", time.Hour, true, time.Now()); err == nil {
+		t.Error("lookup() offline without cache dir succeeded")
+	}
+}
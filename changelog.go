@@ -0,0 +1,161 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	graphql "github.com/cli/shurcooL-graphql"
+)
+
+// changelogOtherTitle is the category of pull requests without a label of
+// any configured category.
+const changelogOtherTitle = "Other"
+
+// changelogSection is a category and its pull requests.
+type changelogSection struct {
+	title        string
+	pullRequests []PullRequestItem
+}
+
+// categorize puts each pull request in the first category with one of its
+// labels, compared case-insensitively, and the rest in "Other". Empty
+// categories are left out.
+func categorize(pullRequests []PullRequestItem, categories []ChangelogCategory) []changelogSection {
+	sections := make([]changelogSection, len(categories)+1)
+	for i, category := range categories {
+		sections[i].title = category.Title
+	}
+	sections[len(categories)].title = changelogOtherTitle
+
+	for _, pr := range pullRequests {
+		i := categoryIndex(&pr, categories)
+		sections[i].pullRequests = append(sections[i].pullRequests, pr)
+	}
+
+	nonEmpty := []changelogSection{}
+	for _, section := range sections {
+		if len(section.pullRequests) > 0 {
+			nonEmpty = append(nonEmpty, section)
+		}
+	}
+	return nonEmpty
+}
+
+func categoryIndex(pri *PullRequestItem, categories []ChangelogCategory) int {
+	for i, category := range categories {
+		for _, label := range category.Labels {
+			for _, prLabel := range pri.Labels {
+				if strings.EqualFold(label, prLabel) {
+					return i
+				}
+			}
+		}
+	}
+	return len(categories)
+}
+
+// writeChangelog writes the pull requests of each repository as a markdown
+// list per category. Like writeMarkdown, authors are written without "@".
+func writeChangelog(w io.Writer, since string, repositories []RepositoryItem, categories []ChangelogCategory) error {
+	fmt.Fprintf(w, "# Changes since %s\n", since)
+	if len(repositories) == 0 {
+		_, err := fmt.Fprintln(w, "\nNo merged pull requests.")
+		return err
+	}
+
+	for _, repo := range repositories {
+		fmt.Fprintf(w, "\n## %s\n", markdownLink(repo.Name, repo.Url))
+		for _, section := range categorize(repo.PullRequestItems, categories) {
+			fmt.Fprintf(w, "\n### %s\n\n", section.title)
+			for _, pr := range section.pullRequests {
+				_, err := fmt.Fprintf(w, "- %s (%s) by %s\n", strings.Join(strings.Fields(pr.Title), " "), markdownLink(fmt.Sprintf("#%d", pr.Number), pr.Url), pr.Author)
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+type commitDate struct {
+	CommittedDate time.Time
+}
+
+type tagQuery struct {
+	Repository struct {
+		Ref *struct {
+			Target struct {
+				Commit commitDate `graphql:"... on Commit"`
+				Tag    struct {
+					Target struct {
+						Commit commitDate `graphql:"... on Commit"`
+					}
+				} `graphql:"... on Tag"`
+			}
+		} `graphql:"ref(qualifiedName: $ref)"`
+	} `graphql:"repository(owner: $owner, name: $name)"`
+}
+
+// fetchTagDate returns the commit date of tag in the repository
+// "<owner>/<repo>" on host. Annotated tags are followed to their commit.
+func fetchTagDate(host string, repository string, tag string) (time.Time, error) {
+	owner, name, ok := strings.Cut(repository, "/")
+	if !ok || owner == "" || name == "" {
+		return time.Time{}, fmt.Errorf("invalid repository: %s", repository)
+	}
+
+	client, err := graphQLClient(host)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return queryTagDate(client, owner, name, tag)
+}
+
+func queryTagDate(client *api.GraphQLClient, owner string, name string, tag string) (time.Time, error) {
+	repository := owner + "/" + name
+	var query = tagQuery{}
+	variables := map[string]interface{}{
+		"owner": graphql.String(owner),
+		"name":  graphql.String(name),
+		"ref":   graphql.String("refs/tags/" + tag),
+	}
+	if err := client.Query("TagDate", &query, variables); err != nil {
+		return time.Time{}, err
+	}
+
+	ref := query.Repository.Ref
+	switch {
+	case ref == nil:
+		return time.Time{}, fmt.Errorf("tag not found: %s@%s", repository, tag)
+	case !ref.Target.Commit.CommittedDate.IsZero():
+		return ref.Target.Commit.CommittedDate, nil
+	case !ref.Target.Tag.Target.Commit.CommittedDate.IsZero():
+		return ref.Target.Tag.Target.Commit.CommittedDate, nil
+	default:
+		return time.Time{}, fmt.Errorf("tag does not point to a commit: %s@%s", repository, tag)
+	}
+}
+
+// resolveSince parses --since of the changelog: a date, a duration before
+// now, or "[<host>/]<owner>/<repo>@<tag>" for the commit date of a tag. It
+// returns the time and how to describe it in the heading.
+func resolveSince(value string, defaultHost string, now time.Time) (time.Time, string, error) {
+	if repository, tag, ok := strings.Cut(value, "@"); ok {
+		host := defaultHost
+		if parts := strings.Split(repository, "/"); len(parts) == 3 {
+			host, repository = parts[0], parts[1]+"/"+parts[2]
+		}
+		since, err := fetchTagDate(host, repository, tag)
+		return since, value, err
+	}
+
+	since, err := parseSince(value, now)
+	if err != nil {
+		return time.Time{}, "", err
+	}
+	return since, since.In(time.Local).Format("2006-01-02"), nil
+}
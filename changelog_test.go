@@ -0,0 +1,152 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+func TestCategorize(t *testing.T) {
+	pullRequests := []PullRequestItem{
+		{Number: 4, Labels: []string{"Bug"}},
+		{Number: 3, Labels: []string{"documentation"}},
+		{Number: 2, Labels: []string{"bug", "enhancement"}},
+		{Number: 1},
+	}
+
+	sections := categorize(pullRequests, defaultChangelogCategories())
+
+	got := map[string][]int{}
+	titles := []string{}
+	for _, section := range sections {
+		titles = append(titles, section.title)
+		for _, pr := range section.pullRequests {
+			got[section.title] = append(got[section.title], pr.Number)
+		}
+	}
+	if strings.Join(titles, ",") != "Features,Fixes,Other" {
+		t.Errorf("titles = %v, want [Features Fixes Other]", titles)
+	}
+	for title, want := range map[string][]int{"Features": {2}, "Fixes": {4}, "Other": {3, 1}} {
+		if len(got[title]) != len(want) || got[title][0] != want[0] {
+			t.Errorf("%s = %v, want %v", title, got[title], want)
+		}
+	}
+}
+
+func TestWriteChangelog(t *testing.T) {
+	repositories := []RepositoryItem{
+		{
+			Name: "test/repo",
+			Url:  "https://github.com/test/repo",
+			PullRequestItems: []PullRequestItem{
+				{Number: 2, Title: "Add export", Author: "alice", Url: "https://github.com/test/repo/pull/2", Labels: []string{"feature"}},
+				{Number: 1, Title: "Fix crash\non start", Author: "bob", Url: "https://github.com/test/repo/pull/1", Labels: []string{"bug"}},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := writeChangelog(&buf, "2024-12-01", repositories, defaultChangelogCategories()); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "# Changes since 2024-12-01\n" +
+		"\n## [test/repo](https://github.com/test/repo)\n" +
+		"\n### Features\n\n" +
+		"- Add export ([#2](https://github.com/test/repo/pull/2)) by alice\n" +
+		"\n### Fixes\n\n" +
+		"- Fix crash on start ([#1](https://github.com/test/repo/pull/1)) by bob\n"
+	if got := buf.String(); got != want {
+		t.Errorf("writeChangelog() = %q, want %q", got, want)
+	}
+}
+
+func TestWriteChangelogEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeChangelog(&buf, "test/repo@v1.0.0", nil, defaultChangelogCategories()); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "# Changes since test/repo@v1.0.0\n\nNo merged pull requests.\n"
+	if got := buf.String(); got != want {
+		t.Errorf("writeChangelog() = %q, want %q", got, want)
+	}
+}
+
+func TestResolveSince(t *testing.T) {
+	now := time.Date(2024, 12, 31, 12, 0, 0, 0, time.Local)
+
+	since, description, err := resolveSince("14d", "", now)
+	if err != nil || !since.Equal(time.Date(2024, 12, 17, 0, 0, 0, 0, time.Local)) || description != "2024-12-17" {
+		t.Errorf("resolveSince(14d) = %v, %q, %v", since, description, err)
+	}
+
+	since, description, err = resolveSince("2024-12-01", "", now)
+	if err != nil || !since.Equal(time.Date(2024, 12, 1, 0, 0, 0, 0, time.Local)) || description != "2024-12-01" {
+		t.Errorf("resolveSince(2024-12-01) = %v, %q, %v", since, description, err)
+	}
+
+	if _, _, err := resolveSince("yesterday", "", now); err == nil {
+		t.Error("resolveSince(yesterday) returned no error")
+	}
+}
+
+func TestQueryTagDate(t *testing.T) {
+	committedDate := time.Date(2024, 12, 1, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		response string
+		want     time.Time
+		wantErr  bool
+	}{
+		{
+			name:     "lightweight tag",
+			response: `{"data": {"repository": {"ref": {"target": {"committedDate": "2024-12-01T10:00:00Z"}}}}}`,
+			want:     committedDate,
+		},
+		{
+			name:     "annotated tag",
+			response: `{"data": {"repository": {"ref": {"target": {"target": {"committedDate": "2024-12-01T10:00:00Z"}}}}}}`,
+			want:     committedDate,
+		},
+		{
+			name:     "missing tag",
+			response: `{"data": {"repository": {"ref": null}}}`,
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := api.NewGraphQLClient(api.ClientOptions{
+				Host:      "github.com",
+				AuthToken: "token",
+				Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Header:     http.Header{"Content-Type": []string{"application/json"}},
+						Body:       io.NopCloser(strings.NewReader(tt.response)),
+						Request:    r,
+					}, nil
+				}),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := queryTagDate(client, "test", "repo", "v1.0.0")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("queryTagDate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("queryTagDate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/atotto/clipboard"
+	"github.com/aymanbagabas/go-osc52/v2"
+)
+
+// copyToClipboard copies text to the system clipboard. Over SSH, or when
+// no system clipboard is available, it asks the terminal to set its
+// clipboard with an OSC 52 escape sequence instead.
+func copyToClipboard(text string) error {
+	if os.Getenv("SSH_TTY") == "" && os.Getenv("SSH_CONNECTION") == "" {
+		if err := clipboard.WriteAll(text); err == nil {
+			return nil
+		}
+	}
+
+	seq := osc52.New(text)
+	if os.Getenv("TMUX") != "" {
+		seq = seq.Tmux()
+	} else if strings.HasPrefix(os.Getenv("TERM"), "screen") {
+		seq = seq.Screen()
+	}
+	_, err := seq.WriteTo(os.Stderr)
+	return err
+}
+
+// pullRequestReference returns the short "owner/repo#N" reference.
+func pullRequestReference(pri *PullRequestItem) string {
+	return fmt.Sprintf("%s#%d", pri.RepositoryName, pri.Number)
+}
+
+var markdownLinkTextReplacer = strings.NewReplacer(`\`, `\\`, "[", `\[`, "]", `\]`)
+
+// markdownLink returns "[title](url)" with brackets in the title escaped.
+func markdownLink(text string, url string) string {
+	return fmt.Sprintf("[%s](%s)", markdownLinkTextReplacer.Replace(text), url)
+}
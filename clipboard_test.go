@@ -0,0 +1,44 @@
+package main
+
+import "testing"
+
+func TestPullRequestReference(t *testing.T) {
+	pri := &PullRequestItem{RepositoryName: "test/repo", Number: 123}
+	expected := "test/repo#123"
+	result := pullRequestReference(pri)
+
+	if result != expected {
+		t.Errorf("pullRequestReference() = %q, want %q", result, expected)
+	}
+}
+
+func TestMarkdownLink(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		url  string
+		want string
+	}{
+		{
+			name: "plain title",
+			text: "Fix bug",
+			url:  "https://github.com/test/repo/pull/1",
+			want: "[Fix bug](https://github.com/test/repo/pull/1)",
+		},
+		{
+			name: "title with brackets",
+			text: "[WIP] Fix \\ bug",
+			url:  "https://github.com/test/repo/pull/2",
+			want: "[\\[WIP\\] Fix \\\\ bug](https://github.com/test/repo/pull/2)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := markdownLink(tt.text, tt.url)
+			if result != tt.want {
+				t.Errorf("markdownLink() = %q, want %q", result, tt.want)
+			}
+		})
+	}
+}
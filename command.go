@@ -1,22 +1,64 @@
 package main
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"os"
+	"os/signal"
 	"runtime/debug"
+	"slices"
+	"strings"
 	"sync"
+	"syscall"
+	"time"
 
 	"github.com/spf13/cobra"
 )
 
+const (
+	formatPlain    = "plain"
+	formatMarkdown = "markdown"
+	formatHTML     = "html"
+	formatCSV      = "csv"
+	formatTSV      = "tsv"
+	formatAtom     = "atom"
+)
+
 type Options struct {
 	Limit             int
+	Hostname          string
+	State             string
+	MergedSince       string
+	MergedAfter       time.Time
+	CacheTTL          time.Duration
+	Offline           bool
 	Excludes          []string
-	Author            string
+	IncludeRepos      []string
+	Authors           []string
+	Teams             []string
 	AdditionalQueries []string
 	Verbose           bool
 	Interactive       bool
 	NoColor           bool
+	Format            string
+	Columns           []string
+	Wrap              bool
+	AlignGlobal       bool
+	// AlignAll pads the title and the columns after it too. It is set by
+	// --columns, while the default layout leaves them unpadded.
+	AlignAll      bool
+	Table         bool
+	Summary       bool
+	JSON          bool
+	Addr          string
+	Interval      time.Duration
+	NotifyWebhook string
+	WebhookFormat string
+	FailIf        []string
+	Quiet         bool
+	Config        *Config
 }
 
 func buildVersion() string {
@@ -29,79 +71,488 @@ func buildVersion() string {
 func rootCmd() *cobra.Command {
 	opts := &Options{}
 	cmd := &cobra.Command{
-		Use:           "gh list-prs <org> [<org>...]",
+		Use:           "list-prs <org> [<org>...]",
 		Short:         "List PRs for one or more orgs",
+		Long:          "List PRs for one or more orgs.\n\nAn org named like a subcommand (stats, serve, digest, changelog) must follow --,\ne.g. gh list-prs -- serve, or it runs the subcommand.",
+		Annotations:   map[string]string{cobra.CommandDisplayNameAnnotation: "gh list-prs"},
 		Version:       buildVersion(),
 		Args:          cobra.MatchAll(cobra.MinimumNArgs(1), cobra.OnlyValidArgs),
 		SilenceErrors: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			orgs := args
 
-			if opts.Limit <= 0 {
-				return errors.New("invalid limit")
+			if err := prepareOptions(opts); err != nil {
+				return err
+			}
+
+			switch opts.Format {
+			case formatPlain, formatMarkdown, formatHTML, formatCSV, formatTSV, formatAtom:
+			default:
+				return fmt.Errorf("invalid format: %s", opts.Format)
+			}
+
+			if opts.State != stateOpen && !cmd.Flags().Changed("columns") {
+				opts.Columns = slices.Insert(slices.Clone(opts.Columns), 1, "state")
+			}
+			if err := validateColumns(opts.Columns); err != nil {
+				return err
+			}
+			opts.AlignAll = cmd.Flags().Changed("columns")
+
+			if opts.Summary && opts.Format != formatPlain {
+				return errors.New("--summary can only be used with the plain format")
+			}
+
+			if opts.Table && opts.Format != formatPlain {
+				return errors.New("--table can only be used with the plain format")
+			}
+
+			if err := validateWebhookFormat(opts.WebhookFormat); err != nil {
+				return err
 			}
 
+			// From here on errors, e.g. a matched --fail-if, are not about
+			// how the command was called.
+			cmd.SilenceUsage = true
 			return run(orgs, opts)
 		},
 	}
 	cmd.SetVersionTemplate("{{.Version}}\n")
 
-	cmd.Flags().StringArrayVarP(&opts.Excludes, "exclude", "e", []string{}, "exclude repositories")
-	cmd.Flags().IntVarP(&opts.Limit, "limit", "l", 50, "Max number of search results in all repository")
-	cmd.Flags().StringVarP(&opts.Author, "author", "a", "", "Filter by author")
-	cmd.Flags().StringArrayVarP(&opts.AdditionalQueries, "additional-query", "q", []string{}, "additional query")
-	cmd.Flags().BoolVarP(&opts.Verbose, "verbose", "v", false, "verbose output")
+	addSearchFlags(cmd, opts, defaultLimit)
 	cmd.Flags().BoolVarP(&opts.Interactive, "interactive", "i", false, "interactive mode")
 	cmd.Flags().BoolVar(&opts.NoColor, "no-color", false, "disable color output and show plain URLs")
+	cmd.Flags().StringVar(&opts.Format, "format", formatPlain, "output format: plain, markdown, html, csv, tsv or atom")
+	cmd.Flags().StringSliceVar(&opts.Columns, "columns", defaultColumns, "columns of the plain format in order: number, repo, author, updated, created, merged, state, title, checks, review, labels, size")
+	cmd.Flags().BoolVar(&opts.Wrap, "wrap", false, "wrap long titles instead of truncating them to the terminal width")
+	cmd.Flags().BoolVar(&opts.AlignGlobal, "align-global", true, "align columns across all repositories instead of per repository")
+	cmd.Flags().BoolVar(&opts.Table, "table", false, "print a single table with a repo column instead of a list per repository")
+	cmd.Flags().BoolVar(&opts.Summary, "summary", false, "print summary statistics after the list")
+	cmd.Flags().StringVar(&opts.NotifyWebhook, "notify-webhook", "", "post a digest of the result to the webhook URL")
+	cmd.Flags().StringVar(&opts.WebhookFormat, "webhook-format", webhookFormatSlack, "webhook payload format: slack, teams or json")
+	cmd.Flags().StringArrayVar(&opts.FailIf, "fail-if", []string{}, "exit with status 2 when the condition holds, e.g. count>0, failing>3, stale>14d, failing.age>7d")
+	cmd.Flags().BoolVar(&opts.Quiet, "quiet", false, "print nothing, useful with --fail-if")
+	cmd.MarkFlagsMutuallyExclusive("quiet", "interactive")
+
+	cmd.AddCommand(statsCmd())
+	cmd.AddCommand(serveCmd())
+	cmd.AddCommand(digestCmd())
+	cmd.AddCommand(changelogCmd())
+	return cmd
+}
+
+func statsCmd() *cobra.Command {
+	opts := &Options{}
+	cmd := &cobra.Command{
+		Use:   "stats <org> [<org>...]",
+		Short: "Show statistics of PRs for one or more orgs",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := prepareOptions(opts); err != nil {
+				return err
+			}
+
+			repositories, err := fetchRepositories(args, opts)
+			if err != nil {
+				return err
+			}
+
+			stats := computeStats(repositories, time.Now())
+			if opts.JSON {
+				return writeStatsJSON(os.Stdout, stats)
+			}
+			return writeStats(os.Stdout, stats)
+		},
+	}
+
+	addSearchFlags(cmd, opts, defaultLimit)
+	cmd.Flags().BoolVar(&opts.JSON, "json", false, "output as JSON")
+	return cmd
+}
+
+func serveCmd() *cobra.Command {
+	opts := &Options{}
+	cmd := &cobra.Command{
+		Use:   "serve <org> [<org>...]",
+		Short: "Serve a dashboard of PRs for one or more orgs over HTTP",
+		Long: `Serve a dashboard of PRs for one or more orgs over HTTP.
+
+The search runs periodically. "/" serves an HTML dashboard, "/api/prs"
+serves the result as JSON and "/metrics" serves Prometheus metrics.`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := prepareOptions(opts); err != nil {
+				return err
+			}
+
+			if opts.Interval <= 0 {
+				return errors.New("invalid interval")
+			}
+
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			return serve(ctx, args, opts)
+		},
+	}
+
+	addSearchFlags(cmd, opts, defaultLimit)
+	cmd.Flags().StringVar(&opts.Addr, "addr", ":8080", "address to listen on")
+	cmd.Flags().DurationVar(&opts.Interval, "interval", 5*time.Minute, "interval between searches")
+	return cmd
+}
+
+func digestCmd() *cobra.Command {
+	opts := &Options{}
+	cmd := &cobra.Command{
+		Use:   "digest <org> [<org>...]",
+		Short: "Post a digest of PRs for one or more orgs to a webhook",
+		Long: `Post a digest of PRs for one or more orgs to a webhook.
+
+Without --notify-webhook, the payload is printed instead of posted.`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := prepareOptions(opts); err != nil {
+				return err
+			}
+
+			if err := validateWebhookFormat(opts.WebhookFormat); err != nil {
+				return err
+			}
+
+			repositories, err := fetchRepositories(args, opts)
+			if err != nil {
+				return err
+			}
+
+			if opts.NotifyWebhook != "" {
+				return notifyWebhook(opts.NotifyWebhook, opts.WebhookFormat, repositories)
+			}
+
+			payload, err := webhookPayload(opts.WebhookFormat, repositories, time.Now())
+			if err != nil {
+				return err
+			}
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(payload)
+		},
+	}
+
+	addSearchFlags(cmd, opts, defaultLimit)
+	cmd.Flags().StringVar(&opts.NotifyWebhook, "notify-webhook", "", "post the digest to the webhook URL")
+	cmd.Flags().StringVar(&opts.WebhookFormat, "webhook-format", webhookFormatSlack, "webhook payload format: slack, teams or json")
 	return cmd
 }
 
+func changelogCmd() *cobra.Command {
+	opts := &Options{}
+	var since string
+	cmd := &cobra.Command{
+		Use:   "changelog <org> [<org>...] --since <date|duration|owner/repo@tag>",
+		Short: "Write release notes of PRs merged in one or more orgs",
+		Long: `Write release notes of PRs merged in one or more orgs as markdown.
+
+PRs merged since --since are listed per repository, grouped into the label
+categories of the config file. Up to 1000 PRs per org are listed, the most
+a search returns, with a warning when more were merged. --since is a date (2024-12-01), a duration
+(14d) or a tag (owner/repo@v1.2.0), which stands for the date of its commit.`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if cmd.Flags().Changed("state") || cmd.Flags().Changed("merged-since") {
+				return errors.New("changelog lists merged PRs, use --since instead of --state and --merged-since")
+			}
+
+			if opts.Offline && strings.Contains(since, "@") {
+				return errors.New("--since with a tag looks up the tag over the network and cannot be used with --offline")
+			}
+
+			opts.State = stateMerged
+			if err := prepareOptions(opts); err != nil {
+				return err
+			}
+
+			mergedAfter, description, err := resolveSince(since, opts.Hostname, time.Now())
+			if err != nil {
+				return fmt.Errorf("invalid since: %w", err)
+			}
+			opts.MergedAfter = mergedAfter
+
+			repositories, err := fetchRepositories(args, opts)
+			if err != nil {
+				return err
+			}
+			return writeChangelog(os.Stdout, description, repositories, opts.Config.Changelog.Categories)
+		},
+	}
+
+	// Release notes list every PR, as far as the search can go.
+	addSearchFlags(cmd, opts, maxSearchResults)
+	cmd.Flags().StringVar(&since, "since", "", "list PRs merged since a date, a duration or the commit of a tag (owner/repo@tag)")
+	_ = cmd.MarkFlagRequired("since")
+	_ = cmd.Flags().MarkHidden("state")
+	_ = cmd.Flags().MarkHidden("merged-since")
+	return cmd
+}
+
+func validateWebhookFormat(format string) error {
+	switch format {
+	case webhookFormatSlack, webhookFormatTeams, webhookFormatJSON:
+		return nil
+	default:
+		return fmt.Errorf("invalid webhook format: %s", format)
+	}
+}
+
+// defaultLimit is the default --limit of the commands listing PRs.
+const defaultLimit = 50
+
+// addSearchFlags adds the flags building the search query, shared by the
+// commands that search pull requests, with limit as the default --limit.
+func addSearchFlags(cmd *cobra.Command, opts *Options, limit int) {
+	cmd.Flags().StringArrayVarP(&opts.Excludes, "exclude", "e", []string{}, "exclude repositories, by name or by glob (*-archive) or regexp (re:^org/legacy-) pattern")
+	cmd.Flags().StringArrayVar(&opts.IncludeRepos, "include-repo", []string{}, "only include repositories matching a name, glob or regexp (re:) pattern")
+	cmd.Flags().IntVarP(&opts.Limit, "limit", "l", limit, fmt.Sprintf("Max number of search results in all repository, up to %d", maxSearchResults))
+	cmd.Flags().StringArrayVarP(&opts.Authors, "author", "a", []string{}, "Filter by author, repeat for any of several authors")
+	cmd.Flags().StringArrayVar(&opts.Teams, "team", []string{}, "Filter by authors in a team, written as org/team-slug")
+	cmd.Flags().StringArrayVarP(&opts.AdditionalQueries, "additional-query", "q", []string{}, "additional query")
+	cmd.Flags().BoolVarP(&opts.Verbose, "verbose", "v", false, "verbose output")
+	cmd.Flags().StringVar(&opts.State, "state", "", "state of PRs: open, closed, merged or all (default open, or merged with --merged-since)")
+	cmd.Flags().StringVar(&opts.MergedSince, "merged-since", "", "only PRs merged since a date (2024-12-01) or for a duration (7d)")
+	cmd.Flags().StringVar(&opts.Hostname, "hostname", "", "GitHub host of orgs given without a host, e.g. a GitHub Enterprise Server hostname")
+	cmd.Flags().DurationVar(&opts.CacheTTL, "cache-ttl", 0, "reuse cached search results younger than this, e.g. 5m")
+	cmd.Flags().BoolVar(&opts.Offline, "offline", false, "show the last cached search results without accessing the network")
+}
+
+// prepareOptions validates the search flags and loads the config file.
+func prepareOptions(opts *Options) error {
+	if opts.Limit <= 0 || opts.Limit > maxSearchResults {
+		return fmt.Errorf("invalid limit: must be between 1 and %d", maxSearchResults)
+	}
+	if opts.Offline && len(opts.Teams) > 0 {
+		return errors.New("--team looks up team members over the network and cannot be used with --offline")
+	}
+
+	if err := prepareState(opts, time.Now()); err != nil {
+		return err
+	}
+
+	config, err := loadConfig(defaultConfigPath())
+	if err != nil {
+		return err
+	}
+	opts.Config = config
+	return nil
+}
+
+// prepareState resolves --state and --merged-since. --merged-since implies
+// the merged state unless --state is given.
+func prepareState(opts *Options, now time.Time) error {
+	if opts.MergedSince != "" {
+		mergedAfter, err := parseSince(opts.MergedSince, now)
+		if err != nil {
+			return fmt.Errorf("invalid merged-since: %w", err)
+		}
+		opts.MergedAfter = mergedAfter
+		if opts.State == "" {
+			opts.State = stateMerged
+		}
+	}
+	switch opts.State {
+	case "":
+		opts.State = stateOpen
+	case stateOpen, stateClosed:
+		if !opts.MergedAfter.IsZero() {
+			return fmt.Errorf("--merged-since cannot be used with --state %s", opts.State)
+		}
+	case stateMerged, stateAll:
+	default:
+		return fmt.Errorf("invalid state: %s", opts.State)
+	}
+	return nil
+}
+
 func run(orgs []string, opts *Options) error {
-	type result struct {
-		repositories []RepositoryItem
-		err          error
+	conditions, err := parseGateConditions(opts.FailIf)
+	if err != nil {
+		return err
+	}
+
+	allRepositories, err := fetchRepositories(orgs, opts)
+	if err != nil {
+		return err
+	}
+
+	if opts.Interactive {
+		if err := printResultInteractive(orgs, allRepositories, opts); err != nil {
+			return err
+		}
+	} else if !opts.Quiet {
+		if err := printResult(orgs, allRepositories, opts); err != nil {
+			return err
+		}
+	}
+
+	if opts.NotifyWebhook != "" {
+		if err := notifyWebhook(opts.NotifyWebhook, opts.WebhookFormat, allRepositories); err != nil {
+			return err
+		}
 	}
 
-	results := make([]result, len(orgs))
+	return checkGate(conditions, allRepositories, time.Now())
+}
+
+// fetchRepositories searches pull requests in each org and returns the
+// repositories of all of them. An org may take several searches, whose
+// results are merged. Searches not answered from the cache are sent in
+// batches, one GraphQL request per batch, concurrently.
+func fetchRepositories(orgs []string, opts *Options) ([]RepositoryItem, error) {
+	cache := &searchCache{dir: defaultCacheDir()}
+	now := time.Now()
+
+	filter, err := newRepoFilter(opts.Excludes, opts.IncludeRepos)
+	if err != nil {
+		return nil, err
+	}
+
+	authors, err := resolveAuthors(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	orgSearches := make([][]*orgSearch, 0, len(orgs))
+	orgFilters := make([]*repoFilter, 0, len(orgs))
+	searches := []*orgSearch{}
+	for _, org := range orgs {
+		target, err := parseSearchTarget(org, opts.Hostname)
+		if err != nil {
+			return nil, err
+		}
+
+		plan, err := planSearch(target.org, authors, opts)
+		if err != nil {
+			return nil, err
+		}
+		if opts.Verbose && len(plan.excludes) > 0 {
+			fmt.Printf("excluding %d repositories after the search to fit the query length limit\n", len(plan.excludes))
+		}
+		orgFilters = append(orgFilters, filter.withExcludedNames(plan.excludes))
+
+		targetSearches := []*orgSearch{}
+		for _, queryString := range plan.queryStrings {
+			if opts.Verbose {
+				if target.host != "" {
+					fmt.Printf("query on %s: %s\n", target.host, queryString)
+				} else {
+					fmt.Printf("query: %s\n", queryString)
+				}
+			}
+
+			s := &orgSearch{host: target.host, queryString: queryString, cacheKey: cacheKey(target.host, queryString, opts.Limit)}
+			s.result, s.done, err = cache.lookup(s.cacheKey, opts.CacheTTL, opts.Offline, now)
+			if err != nil {
+				return nil, err
+			}
+			targetSearches = append(targetSearches, s)
+		}
+		orgSearches = append(orgSearches, targetSearches)
+		searches = append(searches, targetSearches...)
+	}
+
+	batches := batchSearches(searches, searchBatchSize)
+	errs := make([]error, len(batches))
 	var wg sync.WaitGroup
 
-	for i, org := range orgs {
+	for i, batch := range batches {
 		wg.Add(1)
-		go func(i int, org string) {
+		go func(i int, batch []*orgSearch) {
 			defer wg.Done()
-			queryString := formatQueryString(org, opts)
-			if opts.Verbose {
-				fmt.Printf("query: %s\n", queryString)
+			queryStrings := make([]string, len(batch))
+			for j, s := range batch {
+				queryStrings[j] = s.queryString
 			}
-			repositories, err := fetchPullRequests(queryString, opts.Limit)
-			results[i] = result{repositories: repositories, err: err}
-		}(i, org)
+			results, err := fetchPullRequestsBatch(batch[0].host, queryStrings, opts.Limit)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			for j, s := range batch {
+				s.result = results[j]
+				// The cache is best effort; the result is at hand anyway.
+				_ = cache.store(s.cacheKey, cacheEntry{FetchedAt: now, Repositories: s.result.repositories, Total: s.result.total})
+			}
+		}(i, batch)
 	}
 
 	wg.Wait()
 
-	var allRepositories []RepositoryItem
-	for _, r := range results {
-		if r.err != nil {
-			return r.err
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
 		}
-		allRepositories = append(allRepositories, r.repositories...)
 	}
 
-	if opts.Interactive {
-		if err := printResultInteractive(orgs, allRepositories, opts.NoColor); err != nil {
-			return err
+	var allRepositories []RepositoryItem
+	for i, targetSearches := range orgSearches {
+		results := make([]searchResult, len(targetSearches))
+		for j, s := range targetSearches {
+			results[j] = s.result
+		}
+		repositories, truncated := mergeSearchResults(results, opts.Limit)
+		kept := orgFilters[i].apply(repositories)
+		if truncated {
+			if n, found := len(allPullRequests(kept)), len(allPullRequests(repositories)); n < found {
+				warnf("%s: repository filters left %d of the first %d PRs found, raise --limit to list more", orgs[i], n, found)
+			} else {
+				warnf("%s: more than %d PRs match, raise --limit to list more", orgs[i], opts.Limit)
+			}
 		}
-	} else {
-		printResult(allRepositories, opts)
+		allRepositories = append(allRepositories, kept...)
 	}
 
-	return nil
+	return allRepositories, nil
 }
 
-func printResult(repositories []RepositoryItem, opts *Options) {
-	for _, repo := range repositories {
-		repo.printList(opts)
-		fmt.Println()
+// warnf prints a warning to stderr, leaving stdout to the result.
+func warnf(format string, args ...interface{}) {
+	fmt.Fprintf(warningOutput, "warning: "+format+"\n", args...)
+}
+
+// warningOutput is where warnf writes. Tests replace it to check warnings.
+var warningOutput io.Writer = os.Stderr
+
+// resultTitle describes the searched orgs.
+func resultTitle(orgs []string) string {
+	if len(orgs) == 1 {
+		return fmt.Sprintf("PRs in %s", orgs[0])
 	}
+	return fmt.Sprintf("PRs in %d orgs", len(orgs))
+}
+
+func printResult(orgs []string, repositories []RepositoryItem, opts *Options) error {
+	switch opts.Format {
+	case formatMarkdown:
+		return writeMarkdown(os.Stdout, repositories, opts.State != stateOpen)
+	case formatHTML:
+		return writeHTML(os.Stdout, repositories, time.Now())
+	case formatCSV:
+		return writeCSV(os.Stdout, repositories, ',')
+	case formatTSV:
+		return writeCSV(os.Stdout, repositories, '\t')
+	case formatAtom:
+		return writeAtom(os.Stdout, orgs, repositories, time.Now())
+	default:
+		maxWidth := 0
+		if !opts.Wrap {
+			maxWidth = terminalWidth()
+		}
+		if opts.Table {
+			writeTable(os.Stdout, repositories, opts, maxWidth)
+		} else {
+			writePlain(os.Stdout, repositories, opts, maxWidth)
+		}
+		if opts.Summary {
+			return writeStats(os.Stdout, computeStats(repositories, time.Now()))
+		}
+	}
+	return nil
 }
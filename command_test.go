@@ -0,0 +1,177 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func TestPrepareState(t *testing.T) {
+	now := time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name            string
+		state           string
+		mergedSince     string
+		wantState       string
+		wantMergedAfter time.Time
+		wantErr         bool
+	}{
+		{name: "default", wantState: stateOpen},
+		{name: "closed", state: stateClosed, wantState: stateClosed},
+		{name: "merged since implies merged", mergedSince: "7d", wantState: stateMerged, wantMergedAfter: now.AddDate(0, 0, -7)},
+		{name: "merged since with all", state: stateAll, mergedSince: "7d", wantState: stateAll, wantMergedAfter: now.AddDate(0, 0, -7)},
+		{name: "merged since with open", state: stateOpen, mergedSince: "7d", wantErr: true},
+		{name: "invalid merged since", mergedSince: "soon", wantErr: true},
+		{name: "invalid state", state: "draft", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := &Options{State: tt.state, MergedSince: tt.mergedSince}
+			err := prepareState(opts, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("prepareState() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if opts.State != tt.wantState {
+				t.Errorf("State = %q, want %q", opts.State, tt.wantState)
+			}
+			if !opts.MergedAfter.Equal(tt.wantMergedAfter) {
+				t.Errorf("MergedAfter = %v, want %v", opts.MergedAfter, tt.wantMergedAfter)
+			}
+		})
+	}
+}
+
+func TestPrepareStateStableQuery(t *testing.T) {
+	now := time.Date(2024, 12, 31, 12, 0, 0, 0, time.Local)
+	queryStrings := []string{}
+	for _, at := range []time.Time{now, now.Add(time.Second)} {
+		opts := &Options{MergedSince: "7d"}
+		if err := prepareState(opts, at); err != nil {
+			t.Fatal(err)
+		}
+		queryStrings = append(queryStrings, formatQueryString("myorg", opts))
+	}
+	if queryStrings[0] != queryStrings[1] {
+		t.Errorf("queries a second apart = %q and %q, want the same", queryStrings[0], queryStrings[1])
+	}
+}
+
+func TestRootCmdOrgNamedLikeSubcommand(t *testing.T) {
+	root := rootCmd()
+
+	cmd, _, err := root.Find([]string{"serve"})
+	if err != nil || cmd.Name() != "serve" {
+		t.Errorf("Find(serve) = %v, %v, want the serve subcommand", cmd.Name(), err)
+	}
+
+	cmd, args, err := root.Find([]string{"--", "serve"})
+	if err != nil || cmd != root {
+		t.Fatalf("Find(-- serve) = %v, %v, want the root command", cmd.Name(), err)
+	}
+	if err := cmd.ParseFlags(args); err != nil {
+		t.Fatal(err)
+	}
+	if got := cmd.Flags().Args(); !reflect.DeepEqual(got, []string{"serve"}) {
+		t.Errorf("orgs = %q, want [serve]", got)
+	}
+}
+
+func TestRootCmdGateFailureWithoutUsage(t *testing.T) {
+	searched := []string{}
+	fakeGraphQL(t, nil, &searched)
+	warningOutput = io.Discard
+	t.Cleanup(func() { warningOutput = os.Stderr })
+
+	cmd := rootCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs([]string{"myorg", "-a", "alice", "--fail-if", "count>0", "--quiet"})
+
+	var gateErr *gateError
+	if err := cmd.Execute(); !errors.As(err, &gateErr) {
+		t.Fatalf("Execute() error = %v, want a gate error", err)
+	}
+	if out.Len() != 0 {
+		t.Errorf("output = %q, want nothing with --quiet", out.String())
+	}
+}
+
+func TestOfflineWithNetworkLookups(t *testing.T) {
+	if err := prepareOptions(&Options{Limit: 10, Offline: true, Teams: []string{"myorg/platform"}}); err == nil || !strings.Contains(err.Error(), "--offline") {
+		t.Errorf("prepareOptions() with --team and --offline error = %v, want rejected", err)
+	}
+
+	cmd := changelogCmd()
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{"myorg", "--since", "myorg/api@v1.0.0", "--offline"})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "--offline") {
+		t.Errorf("changelog --since <tag> --offline error = %v, want rejected", err)
+	}
+}
+
+func TestWebhookFlagName(t *testing.T) {
+	root := rootCmd()
+	digest, _, err := root.Find([]string{"digest"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, cmd := range []*cobra.Command{root, digest} {
+		if cmd.Flags().Lookup("notify-webhook") == nil {
+			t.Errorf("%s has no --notify-webhook flag", cmd.Name())
+		}
+	}
+}
+
+func TestChangelogCmdFlags(t *testing.T) {
+	cmd := changelogCmd()
+	for _, name := range []string{"state", "merged-since"} {
+		if flag := cmd.Flags().Lookup(name); flag == nil || !flag.Hidden {
+			t.Errorf("--%s is not hidden", name)
+		}
+	}
+	if got := cmd.Flags().Lookup("limit").DefValue; got != "1000" {
+		t.Errorf("--limit default = %s, want 1000", got)
+	}
+}
+
+func TestFetchRepositoriesWarnings(t *testing.T) {
+	tests := []struct {
+		name     string
+		excludes []string
+		want     string
+	}{
+		{"truncated", nil, "warning: myorg: more than 10 PRs match, raise --limit to list more\n"},
+		{"truncated and filtered", []string{"re:/repo$"}, "warning: myorg: repository filters left 0 of the first 10 PRs found, raise --limit to list more\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			searched := []string{}
+			fakeGraphQL(t, nil, &searched)
+			var warnings bytes.Buffer
+			warningOutput = &warnings
+			t.Cleanup(func() { warningOutput = os.Stderr })
+
+			opts := &Options{Limit: 10, State: stateOpen, Authors: []string{"alice"}, Excludes: tt.excludes}
+			if _, err := fetchRepositories([]string{"myorg"}, opts); err != nil {
+				t.Fatal(err)
+			}
+			if got := warnings.String(); got != tt.want {
+				t.Errorf("warnings = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
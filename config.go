@@ -0,0 +1,114 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config is read from config.yml in the gh-list-prs config directory.
+//
+//	keys:
+//	  open: [o, O]
+//	theme:
+//	  success: 33
+//	  failure: 208
+//	changelog:
+//	  categories:
+//	    - title: Features
+//	      labels: [feature]
+type Config struct {
+	// Keys overrides the interactive key bindings by action name.
+	Keys      map[string][]string `yaml:"keys"`
+	Theme     Theme               `yaml:"theme"`
+	Changelog ChangelogConfig     `yaml:"changelog"`
+}
+
+// ChangelogConfig configures the changelog subcommand.
+type ChangelogConfig struct {
+	// Categories group pull requests by label, in order. A pull request
+	// goes to the first category with one of its labels.
+	Categories []ChangelogCategory `yaml:"categories"`
+}
+
+type ChangelogCategory struct {
+	Title  string   `yaml:"title"`
+	Labels []string `yaml:"labels"`
+}
+
+// Theme holds ANSI 256 color indexes shared by the plain and the
+// interactive output.
+type Theme struct {
+	Number   uint8 `yaml:"number"`
+	Author   uint8 `yaml:"author"`
+	Draft    uint8 `yaml:"draft"`
+	Success  uint8 `yaml:"success"`
+	Failure  uint8 `yaml:"failure"`
+	Approved uint8 `yaml:"approved"`
+	Accent   uint8 `yaml:"accent"`
+}
+
+func defaultTheme() Theme {
+	return Theme{
+		Number:   5,
+		Author:   2,
+		Draft:    240,
+		Success:  2,
+		Failure:  1,
+		Approved: 5,
+		Accent:   213,
+	}
+}
+
+func defaultChangelogCategories() []ChangelogCategory {
+	return []ChangelogCategory{
+		{Title: "Features", Labels: []string{"feature", "enhancement"}},
+		{Title: "Fixes", Labels: []string{"bug", "fix"}},
+		{Title: "Chores", Labels: []string{"chore", "dependencies"}},
+	}
+}
+
+func defaultConfig() *Config {
+	return &Config{
+		Keys:      map[string][]string{},
+		Theme:     defaultTheme(),
+		Changelog: ChangelogConfig{Categories: defaultChangelogCategories()},
+	}
+}
+
+func defaultConfigPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "gh-list-prs", "config.yml")
+}
+
+// loadConfig reads the config file at path on top of the defaults. A
+// missing file is not an error.
+func loadConfig(path string) (*Config, error) {
+	config := defaultConfig()
+	if path == "" {
+		return config, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return config, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := yaml.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	if err := validateKeys(config.Keys); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	return config, nil
+}
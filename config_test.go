@@ -0,0 +1,83 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoadConfig(t *testing.T) {
+	t.Run("missing file", func(t *testing.T) {
+		config, err := loadConfig(filepath.Join(t.TempDir(), "config.yml"))
+		if err != nil {
+			t.Fatalf("loadConfig() returned error: %v", err)
+		}
+		if config.Theme != defaultTheme() {
+			t.Errorf("Theme = %+v, want %+v", config.Theme, defaultTheme())
+		}
+	})
+
+	t.Run("overrides", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.yml")
+		content := "keys:\n  open: [o, O]\ntheme:\n  success: 33\n  failure: 208\n"
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		config, err := loadConfig(path)
+		if err != nil {
+			t.Fatalf("loadConfig() returned error: %v", err)
+		}
+
+		want := defaultTheme()
+		want.Success = 33
+		want.Failure = 208
+		if config.Theme != want {
+			t.Errorf("Theme = %+v, want %+v", config.Theme, want)
+		}
+		if !reflect.DeepEqual(config.Keys["open"], []string{"o", "O"}) {
+			t.Errorf("Keys[open] = %v, want [o O]", config.Keys["open"])
+		}
+	})
+
+	t.Run("changelog categories", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.yml")
+		content := "changelog:\n  categories:\n    - title: Breaking\n      labels: [breaking]\n"
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		config, err := loadConfig(path)
+		if err != nil {
+			t.Fatalf("loadConfig() returned error: %v", err)
+		}
+
+		want := []ChangelogCategory{{Title: "Breaking", Labels: []string{"breaking"}}}
+		if !reflect.DeepEqual(config.Changelog.Categories, want) {
+			t.Errorf("Categories = %+v, want %+v", config.Changelog.Categories, want)
+		}
+	})
+
+	t.Run("unknown key action", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.yml")
+		if err := os.WriteFile(path, []byte("keys:\n  copy_urls: [u]\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := loadConfig(path); err == nil {
+			t.Error("loadConfig() returned no error")
+		}
+	})
+
+	t.Run("invalid file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.yml")
+		if err := os.WriteFile(path, []byte("theme: [\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := loadConfig(path); err == nil {
+			t.Error("loadConfig() returned no error")
+		}
+	})
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var csvHeader = []string{
+	"repository",
+	"number",
+	"title",
+	"author",
+	"created_at",
+	"updated_at",
+	"draft",
+	"check_status",
+	"review_decision",
+	"url",
+	"state",
+	"merged_at",
+	"closed_at",
+	"merged_by",
+	"repository_url",
+	"labels",
+	"additions",
+	"deletions",
+}
+
+// csvTime formats t in UTC, or as an empty field when it is zero.
+func csvTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// csvText escapes free text written by anyone, e.g. a PR title, that a
+// spreadsheet would take for a formula, by prefixing it with a quote.
+func csvText(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+// writeCSV writes a header row and a row per pull request, separated by
+// comma, e.g. ',' for CSV and '\t' for TSV. Fields containing the
+// separator, quotes or newlines are quoted. Labels are joined with commas.
+// Text fields are escaped with csvText.
+func writeCSV(w io.Writer, repositories []RepositoryItem, comma rune) error {
+	cw := csv.NewWriter(w)
+	cw.Comma = comma
+
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, repo := range repositories {
+		for _, pr := range repo.PullRequestItems {
+			record := []string{
+				pr.RepositoryName,
+				strconv.Itoa(pr.Number),
+				csvText(pr.Title),
+				csvText(pr.Author),
+				csvTime(pr.CreatedAt),
+				csvTime(pr.UpdatedAt),
+				strconv.FormatBool(pr.IsDraft),
+				pr.CheckStatus,
+				pr.ReviewDecision,
+				pr.Url,
+				pr.State,
+				csvTime(pr.MergedAt),
+				csvTime(pr.ClosedAt),
+				csvText(pr.MergedBy),
+				pr.RepositoryUrl,
+				csvText(strings.Join(pr.Labels, ",")),
+				strconv.Itoa(pr.Additions),
+				strconv.Itoa(pr.Deletions),
+			}
+			if err := cw.Write(record); err != nil {
+				return err
+			}
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestWriteCSV(t *testing.T) {
+	repositories := []RepositoryItem{
+		{
+			Name: "test/repo",
+			PullRequestItems: []PullRequestItem{
+				{
+					Number:         1,
+					Title:          "Fix \"quotes\", commas\nand newlines",
+					Author:         "alice",
+					CreatedAt:      time.Date(2024, 11, 29, 12, 0, 0, 0, time.UTC),
+					UpdatedAt:      time.Date(2024, 11, 30, 12, 0, 0, 0, time.UTC),
+					IsDraft:        true,
+					Url:            "https://github.com/test/repo/pull/1",
+					RepositoryName: "test/repo",
+					CheckStatus:    checkStatusSuccess,
+					ReviewDecision: reviewDecisionApproved,
+					State:          pullRequestStateMerged,
+					MergedAt:       time.Date(2024, 12, 1, 9, 0, 0, 0, time.UTC),
+					ClosedAt:       time.Date(2024, 12, 1, 9, 0, 0, 0, time.UTC),
+					MergedBy:       "bob",
+					RepositoryUrl:  "https://github.com/test/repo",
+					Labels:         []string{"bug", "ui"},
+					Additions:      10,
+					Deletions:      2,
+				},
+				{
+					Number:         2,
+					Title:          "Open",
+					Author:         "carol",
+					CreatedAt:      time.Date(2024, 11, 29, 12, 0, 0, 0, time.UTC),
+					UpdatedAt:      time.Date(2024, 11, 30, 12, 0, 0, 0, time.UTC),
+					Url:            "https://github.com/test/repo/pull/2",
+					RepositoryName: "test/repo",
+					CheckStatus:    checkStatusPending,
+					State:          pullRequestStateOpen,
+					RepositoryUrl:  "https://github.com/test/repo",
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name  string
+		comma rune
+		want  string
+	}{
+		{
+			name:  "csv",
+			comma: ',',
+			want: "repository,number,title,author,created_at,updated_at,draft,check_status,review_decision,url,state,merged_at,closed_at,merged_by,repository_url,labels,additions,deletions\n" +
+				"test/repo,1,\"Fix \"\"quotes\"\", commas\nand newlines\",alice,2024-11-29T12:00:00Z,2024-11-30T12:00:00Z,true,SUCCESS,APPROVED,https://github.com/test/repo/pull/1,MERGED,2024-12-01T09:00:00Z,2024-12-01T09:00:00Z,bob,https://github.com/test/repo,\"bug,ui\",10,2\n" +
+				"test/repo,2,Open,carol,2024-11-29T12:00:00Z,2024-11-30T12:00:00Z,false,PENDING,,https://github.com/test/repo/pull/2,OPEN,,,,https://github.com/test/repo,,0,0\n",
+		},
+		{
+			name:  "tsv",
+			comma: '\t',
+			want: "repository\tnumber\ttitle\tauthor\tcreated_at\tupdated_at\tdraft\tcheck_status\treview_decision\turl\tstate\tmerged_at\tclosed_at\tmerged_by\trepository_url\tlabels\tadditions\tdeletions\n" +
+				"test/repo\t1\t\"Fix \"\"quotes\"\", commas\nand newlines\"\talice\t2024-11-29T12:00:00Z\t2024-11-30T12:00:00Z\ttrue\tSUCCESS\tAPPROVED\thttps://github.com/test/repo/pull/1\tMERGED\t2024-12-01T09:00:00Z\t2024-12-01T09:00:00Z\tbob\thttps://github.com/test/repo\tbug,ui\t10\t2\n" +
+				"test/repo\t2\tOpen\tcarol\t2024-11-29T12:00:00Z\t2024-11-30T12:00:00Z\tfalse\tPENDING\t\thttps://github.com/test/repo/pull/2\tOPEN\t\t\t\thttps://github.com/test/repo\t\t0\t0\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := writeCSV(&buf, repositories, tt.comma); err != nil {
+				t.Fatalf("writeCSV() returned error: %v", err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("writeCSV() = %q, want %q", buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteCSVFormulas(t *testing.T) {
+	repositories := []RepositoryItem{
+		{
+			Name: "test/repo",
+			PullRequestItems: []PullRequestItem{
+				{Number: 1, Title: `=HYPERLINK("https://example.com")`, Author: "alice", Labels: []string{"@SUM(A1)"}},
+				{Number: 2, Title: "+1", Author: "bob", Labels: []string{"-x"}},
+				{Number: 3, Title: "\tcmd", Author: "carol", MergedBy: "\rdave"},
+				{Number: 4, Title: "Fix a=b", Author: "erin", Labels: []string{"bug"}},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := writeCSV(&buf, repositories, ','); err != nil {
+		t.Fatalf("writeCSV() returned error: %v", err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := [][]string{
+		{`'=HYPERLINK("https://example.com")`, "alice", "", "'@SUM(A1)"},
+		{"'+1", "bob", "", "'-x"},
+		{"'\tcmd", "carol", "'\rdave", ""},
+		{"Fix a=b", "erin", "", "bug"},
+	}
+	for i, w := range want {
+		record := records[i+1]
+		got := []string{record[2], record[3], record[13], record[15]}
+		if !reflect.DeepEqual(got, w) {
+			t.Errorf("row %d title, author, merged_by, labels = %q, want %q", i+1, got, w)
+		}
+	}
+}
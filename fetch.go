@@ -2,6 +2,9 @@ package main
 
 import (
 	"fmt"
+	"net/http"
+	"reflect"
+	"slices"
 	"sort"
 	"strings"
 	"time"
@@ -17,7 +20,25 @@ const (
 	checkStatusUnknown = "UNKNOWN"
 )
 
-const reviewDecisionApproved = "APPROVED"
+const (
+	stateOpen   = "open"
+	stateClosed = "closed"
+	stateMerged = "merged"
+	stateAll    = "all"
+)
+
+// Pull request states as reported by the API.
+const (
+	pullRequestStateOpen   = "OPEN"
+	pullRequestStateClosed = "CLOSED"
+	pullRequestStateMerged = "MERGED"
+)
+
+const (
+	reviewDecisionApproved         = "APPROVED"
+	reviewDecisionChangesRequested = "CHANGES_REQUESTED"
+	reviewDecisionReviewRequired   = "REVIEW_REQUIRED"
+)
 
 type Commits struct {
 	Nodes []struct {
@@ -33,16 +54,31 @@ type PullRequest struct {
 	Number    int
 	Title     string
 	Url       string
+	CreatedAt time.Time
 	UpdatedAt time.Time
 	IsDraft   bool
+	State     string
+	MergedAt  time.Time
+	ClosedAt  time.Time
 	Author    struct {
 		Login string
 	}
+	MergedBy struct {
+		Login string
+	}
 	ReviewDecision string
 	Repository     struct {
 		NameWithOwner string
+		Url           string
 	}
 	Commits Commits `graphql:"commits(last: 1)"`
+	Labels  struct {
+		Nodes []struct {
+			Name string
+		}
+	} `graphql:"labels(first: 20)"`
+	Additions int
+	Deletions int
 }
 
 func (pr *PullRequest) toPullRequestItem() PullRequestItem {
@@ -51,97 +87,638 @@ func (pr *PullRequest) toPullRequestItem() PullRequestItem {
 		checkStatus = pr.Commits.Nodes[0].Commit.StatusCheckRollup.State
 	}
 
+	labels := make([]string, 0, len(pr.Labels.Nodes))
+	for _, label := range pr.Labels.Nodes {
+		labels = append(labels, label.Name)
+	}
+
 	return PullRequestItem{
 		Number:         pr.Number,
 		Title:          pr.Title,
 		Author:         pr.Author.Login,
+		CreatedAt:      pr.CreatedAt,
 		UpdatedAt:      pr.UpdatedAt,
 		IsDraft:        pr.IsDraft,
+		State:          pr.State,
+		MergedAt:       pr.MergedAt,
+		ClosedAt:       pr.ClosedAt,
+		MergedBy:       pr.MergedBy.Login,
 		Url:            pr.Url,
 		RepositoryName: pr.Repository.NameWithOwner,
+		RepositoryUrl:  pr.Repository.Url,
 		CheckStatus:    checkStatus,
 		ReviewDecision: pr.ReviewDecision,
+		Labels:         labels,
+		Additions:      pr.Additions,
+		Deletions:      pr.Deletions,
 	}
 }
 
 type Search struct {
+	IssueCount int
+	PageInfo   struct {
+		HasNextPage bool
+		EndCursor   string
+	}
 	Nodes []struct {
 		PullRequest `graphql:"... on PullRequest"`
 	}
 }
 
-type searchQuery struct {
-	Search `graphql:"search(first: $first, type: ISSUE, query: $query)"`
-}
-
 type RepositoryItem struct {
-	Name             string
-	PullRequestItems []PullRequestItem
+	Name             string            `json:"name"`
+	Url              string            `json:"url"`
+	PullRequestItems []PullRequestItem `json:"pull_requests"`
 }
 
 type PullRequestItem struct {
-	Number         int
-	Title          string
-	Author         string
-	UpdatedAt      time.Time
-	IsDraft        bool
-	Url            string
-	RepositoryName string
-	CheckStatus    string
-	ReviewDecision string
+	Number         int       `json:"number"`
+	Title          string    `json:"title"`
+	Author         string    `json:"author"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+	IsDraft        bool      `json:"is_draft"`
+	State          string    `json:"state"`
+	MergedAt       time.Time `json:"merged_at,omitzero"`
+	ClosedAt       time.Time `json:"closed_at,omitzero"`
+	MergedBy       string    `json:"merged_by,omitempty"`
+	Url            string    `json:"url"`
+	RepositoryName string    `json:"repository"`
+	RepositoryUrl  string    `json:"repository_url"`
+	CheckStatus    string    `json:"check_status"`
+	ReviewDecision string    `json:"review_decision"`
+	Labels         []string  `json:"labels"`
+	Additions      int       `json:"additions"`
+	Deletions      int       `json:"deletions"`
+}
+
+// searchTarget is an org to search on a host. An empty host is the default
+// host of gh.
+type searchTarget struct {
+	host string
+	org  string
+}
+
+// parseSearchTarget parses "<org>" on defaultHost or "<host>/<org>".
+func parseSearchTarget(arg string, defaultHost string) (searchTarget, error) {
+	parts := strings.Split(arg, "/")
+	switch {
+	case len(parts) == 1 && parts[0] != "":
+		return searchTarget{host: defaultHost, org: parts[0]}, nil
+	case len(parts) == 2 && parts[0] != "" && parts[1] != "":
+		return searchTarget{host: parts[0], org: parts[1]}, nil
+	default:
+		return searchTarget{}, fmt.Errorf("invalid org: %s", arg)
+	}
 }
 
+// stateQualifiers maps --state to search qualifiers. "is:closed" alone
+// would include merged pull requests.
+var stateQualifiers = map[string]string{
+	stateOpen:   "is:open ",
+	stateClosed: "is:closed is:unmerged ",
+	stateMerged: "is:merged ",
+	stateAll:    "",
+}
+
+// parseSince parses a date ("2024-12-01") or a duration before now ("7d",
+// "36h"). A duration goes back to the start of its day, so that the search
+// query, and the cache key with it, stays the same all day long.
+func parseSince(s string, now time.Time) (time.Time, error) {
+	if t, err := time.ParseInLocation("2006-01-02", s, time.Local); err == nil {
+		return t, nil
+	}
+	d, err := parseAge(s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date or duration %q", s)
+	}
+	y, m, day := now.Add(-d).In(time.Local).Date()
+	return time.Date(y, m, day, 0, 0, 0, 0, time.Local), nil
+}
+
+// formatQueryString returns the search query of org as one query, however
+// long. planSearch splits it into queries GitHub accepts.
 func formatQueryString(org string, opts *Options) string {
-	queryString := fmt.Sprintf("is:open is:pr archived:false org:%s", org)
+	queryString := formatQueryBase("org:"+org, opts)
+	for _, name := range excludedRepositories(org, opts) {
+		queryString += fmt.Sprintf(" -repo:%s", name)
+	}
+	for _, author := range opts.Authors {
+		queryString += fmt.Sprintf(" author:%s", author)
+	}
+	return queryString
+}
+
+// formatQueryBase returns the search query without excludes and authors,
+// of scope, e.g. "org:myorg", or of no scope when it is empty.
+func formatQueryBase(scope string, opts *Options) string {
+	state, ok := stateQualifiers[opts.State]
+	if !ok {
+		state = stateQualifiers[stateOpen]
+	}
+	queryString := fmt.Sprintf("%sis:pr archived:false", state)
+	if scope != "" {
+		queryString += " " + scope
+	}
+	if !opts.MergedAfter.IsZero() {
+		queryString += fmt.Sprintf(" merged:>=%s", opts.MergedAfter.UTC().Format("2006-01-02T15:04:05Z"))
+	}
+	for _, query := range opts.AdditionalQueries {
+		queryString += fmt.Sprintf(" %s", query)
+	}
+	return queryString
+}
+
+// excludedRepositories returns the "owner/repo" names of the --exclude
+// values that are names rather than patterns. Names without an owner are
+// in org.
+func excludedRepositories(org string, opts *Options) []string {
+	names := []string{}
 	for _, exclude := range opts.Excludes {
+		if isRepoPattern(exclude) {
+			continue
+		}
 		if strings.Contains(exclude, "/") {
-			queryString += fmt.Sprintf(" -repo:%s", exclude)
+			names = append(names, exclude)
 		} else {
-			queryString += fmt.Sprintf(" -repo:%s/%s", org, exclude)
+			names = append(names, org+"/"+exclude)
 		}
 	}
-	if opts.Author != "" {
-		queryString += fmt.Sprintf(" author:%s", opts.Author)
+	return names
+}
+
+// includedRepositories returns the "owner/repo" names of the --include-repo
+// values that are names rather than patterns, and are in org and not
+// excluded. Names without an owner are in org.
+func includedRepositories(org string, opts *Options) []string {
+	excluded := excludedRepositories(org, opts)
+	names := []string{}
+	for _, include := range opts.IncludeRepos {
+		if isRepoPattern(include) {
+			continue
+		}
+		name := include
+		if !strings.Contains(name, "/") {
+			name = org + "/" + name
+		}
+		owner, _, _ := strings.Cut(name, "/")
+		if !strings.EqualFold(owner, org) || slices.ContainsFunc(excluded, func(e string) bool { return strings.EqualFold(e, name) }) {
+			continue
+		}
+		names = append(names, name)
 	}
-	for _, query := range opts.AdditionalQueries {
-		queryString += fmt.Sprintf(" %s", query)
+	return names
+}
+
+const (
+	// maxQueryLength is the longest search query GitHub accepts.
+	maxQueryLength = 256
+	// maxQueryOperators is the most AND, OR and NOT operators GitHub
+	// accepts in a search query.
+	maxQueryOperators = 5
+)
+
+// searchPlan is how an org is searched: the search queries, and the
+// excluded repositories that did not fit in them and are filtered
+// client-side instead.
+type searchPlan struct {
+	queryStrings []string
+	excludes     []string
+}
+
+// planSearch returns the searches of org for PRs by any of authors, or by
+// anyone when authors is empty. Repeated author qualifiers match any of
+// them, and are split into as many queries as needed to keep each within
+// maxQueryLength. Excluded repositories go in the queries as long as room
+// for one author qualifier is left.
+//
+// Repositories included by name are searched with repo: qualifiers instead
+// of the org, so that PRs of other repositories cannot fill --limit before
+// theirs. The org is still searched, and filtered client-side, when
+// --include-repo has patterns too.
+//
+// A -q value chaining alternatives with OR is split into several searches
+// too when the query would be too long or have too many operators. Only a
+// query that cannot be split that way is an error.
+func planSearch(org string, authors []string, opts *Options) (searchPlan, error) {
+	qualifiers := make([]string, len(authors))
+	reserve := 0
+	for i, author := range authors {
+		qualifiers[i] = "author:" + author
+		reserve = max(reserve, 1+len(qualifiers[i]))
 	}
 
-	return queryString
+	names := includedRepositories(org, opts)
+	repos := make([]string, len(names))
+	scope := len(" org:" + org)
+	for i, name := range names {
+		repos[i] = "repo:" + name
+		scope = max(scope, 1+len(repos[i]))
+	}
+	searchOrg := len(names) == 0 || slices.ContainsFunc(opts.IncludeRepos, isRepoPattern)
+
+	variants := splitAdditionalQueries(opts.AdditionalQueries, scope+reserve)
+	bases := make([]string, len(variants))
+	longest := 0
+	for i, queries := range variants {
+		variant := *opts
+		variant.AdditionalQueries = queries
+		bases[i] = formatQueryBase("", &variant)
+		longest = max(longest, len(bases[i]))
+	}
+
+	// The excludes that fit in the longest query go in all of them, and the
+	// others are filtered client-side from the results of all of them.
+	plan := searchPlan{}
+	excludes := ""
+	if searchOrg {
+		for _, name := range excludedRepositories(org, opts) {
+			qualifier := " -repo:" + name
+			if longest+len(" org:"+org)+len(excludes)+len(qualifier)+reserve <= maxQueryLength {
+				excludes += qualifier
+			} else {
+				plan.excludes = append(plan.excludes, name)
+			}
+		}
+	}
+
+	for _, base := range bases {
+		if searchOrg {
+			plan.queryStrings = append(plan.queryStrings, splitQualifiers(base+" org:"+org+excludes, qualifiers, maxQueryLength)...)
+		}
+		if len(repos) > 0 {
+			// Repeated repo: qualifiers match any of the repositories too.
+			for _, repoBase := range splitQualifiers(base, repos, maxQueryLength-reserve) {
+				plan.queryStrings = append(plan.queryStrings, splitQualifiers(repoBase, qualifiers, maxQueryLength)...)
+			}
+		}
+	}
+
+	for _, queryString := range plan.queryStrings {
+		if err := validateQueryString(queryString); err != nil {
+			return searchPlan{}, err
+		}
+	}
+	return plan, nil
 }
 
-func fetchPullRequests(queryString string, limit int) ([]RepositoryItem, error) {
-	client, err := api.DefaultGraphQLClient()
-	if err != nil {
-		return nil, err
+// splitAdditionalQueries returns the -q values of each search. A value
+// chaining alternatives with OR matches any of them, so while a search
+// would be longer than maxQueryLength with room bytes added, or have more
+// than maxQueryOperators operators, the largest group of alternatives it
+// has is split in two, each searched with the groups of the other values.
+func splitAdditionalQueries(queries []string, room int) [][]string {
+	// groups[i] are the groups of alternatives of queries[i], each searched
+	// separately.
+	groups := make([][][]string, len(queries))
+	for i, query := range queries {
+		groups[i] = [][]string{orTerms(query)}
+	}
+
+	for {
+		variants := combineGroups(groups)
+		i, j := -1, -1
+		for _, variant := range variants {
+			queryString := formatQueryBase("", &Options{AdditionalQueries: joinGroups(groups, variant)})
+			if len(queryString)+room <= maxQueryLength && countQueryOperators(queryString) <= maxQueryOperators {
+				continue
+			}
+			// When nothing is left to split, validateQueryString reports
+			// the query.
+			if i, j = largestGroup(groups, variant); i >= 0 {
+				break
+			}
+		}
+		if i < 0 {
+			result := make([][]string, len(variants))
+			for k, variant := range variants {
+				result[k] = joinGroups(groups, variant)
+			}
+			return result
+		}
+		half := len(groups[i][j]) / 2
+		groups[i] = slices.Insert(groups[i], j+1, groups[i][j][half:])
+		groups[i][j] = groups[i][j][:half]
 	}
+}
 
-	var query = searchQuery{}
-	variables := map[string]interface{}{
-		"first": graphql.Int(limit),
-		"query": graphql.String(queryString),
+// orTerms returns the alternatives of query when it chains them with OR
+// outside quotes and parentheses, and query alone otherwise. Alternatives
+// made of several terms are not split, since the precedence of OR over
+// their implicit AND is not documented.
+func orTerms(query string) []string {
+	terms := []string{}
+	current := []string{}
+	items := 0
+	quoted, depth := false, 0
+	for _, field := range strings.Fields(query) {
+		if !quoted && depth == 0 {
+			switch field {
+			case "OR":
+				if items != 1 {
+					return []string{query}
+				}
+				terms = append(terms, strings.Join(current, " "))
+				current, items = nil, 0
+				continue
+			case "AND", "NOT":
+				return []string{query}
+			}
+			items++
+		}
+		current = append(current, field)
+		if strings.Count(field, `"`)%2 == 1 {
+			quoted = !quoted
+		}
+		if !quoted {
+			depth += strings.Count(field, "(") - strings.Count(field, ")")
+		}
 	}
-	err = client.Query("PullRequests", &query, variables)
+	if items != 1 || len(terms) == 0 {
+		return []string{query}
+	}
+	return append(terms, strings.Join(current, " "))
+}
+
+// joinGroups returns the -q values of variant, a choice of one group of
+// alternatives per value as indexes in groups.
+func joinGroups(groups [][][]string, variant []int) []string {
+	queries := make([]string, len(variant))
+	for i, j := range variant {
+		queries[i] = strings.Join(groups[i][j], " OR ")
+	}
+	return queries
+}
+
+// combineGroups returns every choice of one group of alternatives per -q
+// value.
+func combineGroups(groups [][][]string) [][]int {
+	variants := [][]int{{}}
+	for _, valueGroups := range groups {
+		next := [][]int{}
+		for _, variant := range variants {
+			for j := range valueGroups {
+				next = append(next, append(slices.Clone(variant), j))
+			}
+		}
+		variants = next
+	}
+	return variants
+}
+
+// largestGroup returns the position in groups of the largest group of
+// alternatives variant uses, or -1 when none has more than one.
+func largestGroup(groups [][][]string, variant []int) (int, int) {
+	bestI, bestJ := -1, -1
+	for i, j := range variant {
+		if n := len(groups[i][j]); n > 1 && (bestI < 0 || n > len(groups[bestI][bestJ])) {
+			bestI, bestJ = i, j
+		}
+	}
+	return bestI, bestJ
+}
+
+// validateQueryString reports a query GitHub would reject, with a clearer
+// error than the API gives.
+func validateQueryString(queryString string) error {
+	if n := countQueryOperators(queryString); n > maxQueryOperators {
+		return fmt.Errorf("search query has %d AND, OR and NOT operators, at most %d are allowed: %s", n, maxQueryOperators, queryString)
+	}
+	if len(queryString) > maxQueryLength {
+		return fmt.Errorf("search query is %d characters long, at most %d are allowed: %s", len(queryString), maxQueryLength, queryString)
+	}
+	return nil
+}
+
+// countQueryOperators counts the AND, OR and NOT operators of queryString
+// outside quoted phrases.
+func countQueryOperators(queryString string) int {
+	count := 0
+	quoted := false
+	for _, field := range strings.Fields(queryString) {
+		switch {
+		case quoted:
+		case field == "AND" || field == "OR" || field == "NOT":
+			count++
+		}
+		if strings.Count(field, `"`)%2 == 1 {
+			quoted = !quoted
+		}
+	}
+	return count
+}
+
+// splitQualifiers appends qualifiers to base, starting another query
+// whenever one would grow longer than maxLength.
+func splitQualifiers(base string, qualifiers []string, maxLength int) []string {
+	if len(qualifiers) == 0 {
+		return []string{base}
+	}
+
+	queryStrings := []string{}
+	current := base
+	for _, qualifier := range qualifiers {
+		if current != base && len(current)+1+len(qualifier) > maxLength {
+			queryStrings = append(queryStrings, current)
+			current = base
+		}
+		current += " " + qualifier
+	}
+	return append(queryStrings, current)
+}
+
+// graphQLClient returns a client for host, or for the default host of gh
+// when host is empty.
+func graphQLClient(host string) (*api.GraphQLClient, error) {
+	return api.NewGraphQLClient(api.ClientOptions{Host: host, Transport: graphQLTransport})
+}
+
+// graphQLTransport is the transport of GraphQL clients, nil for the default
+// one. Tests replace it to fake responses.
+var graphQLTransport http.RoundTripper
+
+// searchBatchSize is the maximum number of searches sent in one GraphQL
+// request.
+const searchBatchSize = 10
+
+// orgSearch is a search of an org and, once done, its result.
+type orgSearch struct {
+	host        string
+	queryString string
+	cacheKey    string
+	result      searchResult
+	done        bool
+}
+
+// searchResult is the pull requests a search found, grouped by repository,
+// and the number of pull requests matching it, which is larger when the
+// limit cut the result.
+type searchResult struct {
+	repositories []RepositoryItem
+	total        int
+}
+
+// truncated reports whether the search matched more pull requests than it
+// returned.
+func (r searchResult) truncated() bool {
+	return r.total > len(allPullRequests(r.repositories))
+}
+
+// batchSearches groups the searches not done yet by host, in batches of at
+// most size searches.
+func batchSearches(searches []*orgSearch, size int) [][]*orgSearch {
+	batches := [][]*orgSearch{}
+	open := map[string]int{}
+	for _, s := range searches {
+		if s.done {
+			continue
+		}
+		i, ok := open[s.host]
+		if !ok || len(batches[i]) == size {
+			i = len(batches)
+			open[s.host] = i
+			batches = append(batches, nil)
+		}
+		batches[i] = append(batches[i], s)
+	}
+	return batches
+}
+
+const (
+	// searchPageSize is the most results a search returns per request.
+	searchPageSize = 100
+	// maxSearchResults is the most results GitHub returns for a search.
+	maxSearchResults = 1000
+)
+
+// searchBatchQuery builds a query struct with an aliased search field per
+// query string, "search0: search(..., after: $after0, query: $query0)" and
+// so on, and its variables. A nil cursor starts at the first result.
+func searchBatchQuery(queryStrings []string, first int, cursors []*graphql.String) (reflect.Value, map[string]interface{}) {
+	fields := make([]reflect.StructField, len(queryStrings))
+	variables := map[string]interface{}{"first": graphql.Int(first)}
+	for i, queryString := range queryStrings {
+		fields[i] = reflect.StructField{
+			Name: fmt.Sprintf("Search%d", i),
+			Type: reflect.TypeOf(Search{}),
+			Tag:  reflect.StructTag(fmt.Sprintf(`graphql:"search%d: search(first: $first, after: $after%d, type: ISSUE, query: $query%d)"`, i, i, i)),
+		}
+		variables[fmt.Sprintf("query%d", i)] = graphql.String(queryString)
+		variables[fmt.Sprintf("after%d", i)] = cursors[i]
+	}
+	return reflect.New(reflect.StructOf(fields)), variables
+}
+
+// fetchPullRequestsBatch runs the searches on host together and returns
+// the result of each search in order.
+func fetchPullRequestsBatch(host string, queryStrings []string, limit int) ([]searchResult, error) {
+	client, err := graphQLClient(host)
 	if err != nil {
 		return nil, err
 	}
+	return searchPullRequestsBatch(client, queryStrings, limit)
+}
+
+// searchPullRequestsBatch fetches up to limit pull requests per search, a
+// page of each search still having more per GraphQL request.
+func searchPullRequestsBatch(client *api.GraphQLClient, queryStrings []string, limit int) ([]searchResult, error) {
+	results := make([]searchResult, len(queryStrings))
+	pullRequests := make([][]PullRequest, len(queryStrings))
+	cursors := make([]*graphql.String, len(queryStrings))
+	pending := make([]int, len(queryStrings))
+	for i := range pending {
+		pending[i] = i
+	}
 
-	pullRequests := make([]PullRequest, 0, len(query.Nodes))
-	for _, node := range query.Nodes {
-		pullRequests = append(pullRequests, node.PullRequest)
+	for fetched := 0; len(pending) > 0; {
+		first := min(limit-fetched, searchPageSize)
+		pageQueries := make([]string, len(pending))
+		pageCursors := make([]*graphql.String, len(pending))
+		for j, i := range pending {
+			pageQueries[j] = queryStrings[i]
+			pageCursors[j] = cursors[i]
+		}
+		query, variables := searchBatchQuery(pageQueries, first, pageCursors)
+		if err := client.Query("PullRequests", query.Interface(), variables); err != nil {
+			return nil, err
+		}
+
+		next := []int{}
+		for j, i := range pending {
+			search := query.Elem().Field(j).Interface().(Search)
+			results[i].total = search.IssueCount
+			for _, node := range search.Nodes {
+				pullRequests[i] = append(pullRequests[i], node.PullRequest)
+			}
+			if search.PageInfo.HasNextPage && fetched+first < limit {
+				cursors[i] = graphql.NewString(graphql.String(search.PageInfo.EndCursor))
+				next = append(next, i)
+			}
+		}
+		pending = next
+		fetched += first
 	}
 
-	return groupAndSortPullRequests(pullRequests), nil
+	for i := range results {
+		results[i].repositories = groupAndSortPullRequests(pullRequests[i])
+	}
+	return results, nil
 }
 
 // groupAndSortPullRequests groups pull requests by repository, sorts pull
 // requests within each repository by Number descending, and sorts
 // repositories by name.
 func groupAndSortPullRequests(pullRequests []PullRequest) []RepositoryItem {
-	repoMap := map[string][]PullRequestItem{}
+	items := make([]PullRequestItem, 0, len(pullRequests))
 	for _, pr := range pullRequests {
-		name := pr.Repository.NameWithOwner
-		repoMap[name] = append(repoMap[name], pr.toPullRequestItem())
+		items = append(items, pr.toPullRequestItem())
+	}
+	return groupPullRequestItems(items)
+}
+
+// mergeSearchResults merges the results of the searches of an org, as
+// mergeRepositories does. Each search returns up to limit pull requests;
+// when together they found more, the limit most recently created ones are
+// kept. truncated reports whether more pull requests matched than are
+// returned.
+func mergeSearchResults(results []searchResult, limit int) (repositories []RepositoryItem, truncated bool) {
+	var all []RepositoryItem
+	for _, result := range results {
+		all = append(all, result.repositories...)
+		truncated = truncated || result.truncated()
+	}
+
+	merged := mergeRepositories(all)
+	items := allPullRequests(merged)
+	if len(items) <= limit {
+		return merged, truncated
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].CreatedAt.After(items[j].CreatedAt)
+	})
+	return groupPullRequestItems(items[:limit]), true
+}
+
+// mergeRepositories merges the results of several searches into one, as
+// groupAndSortPullRequests does, dropping pull requests found twice.
+func mergeRepositories(repositories []RepositoryItem) []RepositoryItem {
+	seen := map[string]bool{}
+	items := []PullRequestItem{}
+	for _, pr := range allPullRequests(repositories) {
+		if seen[pr.Url] {
+			continue
+		}
+		seen[pr.Url] = true
+		items = append(items, pr)
+	}
+	return groupPullRequestItems(items)
+}
+
+func groupPullRequestItems(pullRequestItems []PullRequestItem) []RepositoryItem {
+	repoMap := map[string][]PullRequestItem{}
+	for _, pr := range pullRequestItems {
+		name := pr.RepositoryName
+		repoMap[name] = append(repoMap[name], pr)
 	}
 
 	repoNames := make([]string, 0, len(repoMap))
@@ -156,8 +733,28 @@ func groupAndSortPullRequests(pullRequests []PullRequest) []RepositoryItem {
 		sort.Slice(items, func(i, j int) bool {
 			return items[i].Number > items[j].Number
 		})
-		repositories = append(repositories, RepositoryItem{Name: name, PullRequestItems: items})
+		repositories = append(repositories, RepositoryItem{Name: name, Url: items[0].RepositoryUrl, PullRequestItems: items})
 	}
 
 	return repositories
 }
+
+type viewerQuery struct {
+	Viewer struct {
+		Login string
+	}
+}
+
+func fetchViewerLogin(host string) (string, error) {
+	client, err := graphQLClient(host)
+	if err != nil {
+		return "", err
+	}
+
+	var query = viewerQuery{}
+	if err := client.Query("Viewer", &query, nil); err != nil {
+		return "", err
+	}
+
+	return query.Viewer.Login, nil
+}
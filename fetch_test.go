@@ -1,9 +1,16 @@
 package main
 
 import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/cli/go-gh/v2/pkg/api"
 )
 
 func TestFormatQueryString(t *testing.T) {
@@ -20,7 +27,6 @@ func TestFormatQueryString(t *testing.T) {
 			opts: &Options{
 				Excludes:          []string{},
 				AdditionalQueries: []string{},
-				Author:            "",
 				Verbose:           false,
 			},
 			wantContains: []string{
@@ -37,7 +43,6 @@ func TestFormatQueryString(t *testing.T) {
 			opts: &Options{
 				Excludes:          []string{"repo1", "org2/repo2"},
 				AdditionalQueries: []string{},
-				Author:            "",
 				Verbose:           false,
 			},
 			wantContains: []string{
@@ -53,7 +58,7 @@ func TestFormatQueryString(t *testing.T) {
 			opts: &Options{
 				Excludes:          []string{},
 				AdditionalQueries: []string{},
-				Author:            "alice",
+				Authors:           []string{"alice"},
 				Verbose:           false,
 			},
 			wantContains: []string{
@@ -68,7 +73,6 @@ func TestFormatQueryString(t *testing.T) {
 			opts: &Options{
 				Excludes:          []string{},
 				AdditionalQueries: []string{"label:bug", "state:draft"},
-				Author:            "",
 				Verbose:           false,
 			},
 			wantContains: []string{
@@ -84,7 +88,7 @@ func TestFormatQueryString(t *testing.T) {
 			opts: &Options{
 				Excludes:          []string{"repo1", "org2/repo2"},
 				AdditionalQueries: []string{"label:bug"},
-				Author:            "bob",
+				Authors:           []string{"bob"},
 				Verbose:           false,
 			},
 			wantContains: []string{
@@ -99,6 +103,42 @@ func TestFormatQueryString(t *testing.T) {
 			},
 			wantNotContain: []string{},
 		},
+		{
+			name: "with exclude patterns",
+			org:  "myorg",
+			opts: &Options{
+				Excludes: []string{"repo1", "*-archive", "re:^myorg/legacy-"},
+			},
+			wantContains:   []string{"-repo:myorg/repo1"},
+			wantNotContain: []string{"*-archive", "legacy"},
+		},
+		{
+			name: "closed state",
+			org:  "myorg",
+			opts: &Options{State: stateClosed},
+			wantContains: []string{
+				"is:closed is:unmerged",
+				"is:pr",
+			},
+			wantNotContain: []string{"is:open", "is:merged"},
+		},
+		{
+			name: "merged since",
+			org:  "myorg",
+			opts: &Options{State: stateMerged, MergedAfter: time.Date(2024, 12, 1, 9, 30, 0, 0, time.UTC)},
+			wantContains: []string{
+				"is:merged",
+				"merged:>=2024-12-01T09:30:00Z",
+			},
+			wantNotContain: []string{"is:open"},
+		},
+		{
+			name:           "all states",
+			org:            "myorg",
+			opts:           &Options{State: stateAll},
+			wantContains:   []string{"is:pr archived:false org:myorg"},
+			wantNotContain: []string{"is:open", "is:closed", "is:merged"},
+		},
 	}
 
 	for _, tt := range tests {
@@ -129,13 +169,17 @@ func TestToPullRequestItem(t *testing.T) {
 		{
 			name: "PR with status check",
 			pr: &PullRequest{
-				Number:     123,
-				Title:      "Fix bug",
-				Url:        "https://github.com/test/repo/pull/123",
-				UpdatedAt:  time.Date(2024, 11, 30, 12, 0, 0, 0, time.UTC),
-				IsDraft:    false,
-				Author:     struct{ Login string }{Login: "alice"},
-				Repository: struct{ NameWithOwner string }{NameWithOwner: "test/repo"},
+				Number:    123,
+				Title:     "Fix bug",
+				Url:       "https://github.com/test/repo/pull/123",
+				CreatedAt: time.Date(2024, 11, 29, 12, 0, 0, 0, time.UTC),
+				UpdatedAt: time.Date(2024, 11, 30, 12, 0, 0, 0, time.UTC),
+				IsDraft:   false,
+				Author:    struct{ Login string }{Login: "alice"},
+				Repository: struct {
+					NameWithOwner string
+					Url           string
+				}{NameWithOwner: "test/repo", Url: "https://github.com/test/repo"},
 				Commits: Commits{
 					Nodes: []struct {
 						Commit struct {
@@ -164,23 +208,28 @@ func TestToPullRequestItem(t *testing.T) {
 				Number:         123,
 				Title:          "Fix bug",
 				Author:         "alice",
+				CreatedAt:      time.Date(2024, 11, 29, 12, 0, 0, 0, time.UTC),
 				UpdatedAt:      time.Date(2024, 11, 30, 12, 0, 0, 0, time.UTC),
 				IsDraft:        false,
 				Url:            "https://github.com/test/repo/pull/123",
 				RepositoryName: "test/repo",
+				RepositoryUrl:  "https://github.com/test/repo",
 				CheckStatus:    "SUCCESS",
 			},
 		},
 		{
 			name: "PR without commit info",
 			pr: &PullRequest{
-				Number:     456,
-				Title:      "Add feature",
-				Url:        "https://github.com/test/repo/pull/456",
-				UpdatedAt:  time.Date(2024, 11, 30, 13, 0, 0, 0, time.UTC),
-				IsDraft:    true,
-				Author:     struct{ Login string }{Login: "bob"},
-				Repository: struct{ NameWithOwner string }{NameWithOwner: "test/repo"},
+				Number:    456,
+				Title:     "Add feature",
+				Url:       "https://github.com/test/repo/pull/456",
+				UpdatedAt: time.Date(2024, 11, 30, 13, 0, 0, 0, time.UTC),
+				IsDraft:   true,
+				Author:    struct{ Login string }{Login: "bob"},
+				Repository: struct {
+					NameWithOwner string
+					Url           string
+				}{NameWithOwner: "test/repo", Url: "https://github.com/test/repo"},
 				Commits: Commits{
 					Nodes: []struct {
 						Commit struct {
@@ -199,19 +248,23 @@ func TestToPullRequestItem(t *testing.T) {
 				IsDraft:        true,
 				Url:            "https://github.com/test/repo/pull/456",
 				RepositoryName: "test/repo",
+				RepositoryUrl:  "https://github.com/test/repo",
 				CheckStatus:    checkStatusUnknown,
 			},
 		},
 		{
 			name: "PR with failure check status",
 			pr: &PullRequest{
-				Number:     789,
-				Title:      "WIP",
-				Url:        "https://github.com/test/repo/pull/789",
-				UpdatedAt:  time.Date(2024, 11, 30, 14, 0, 0, 0, time.UTC),
-				IsDraft:    false,
-				Author:     struct{ Login string }{Login: "charlie"},
-				Repository: struct{ NameWithOwner string }{NameWithOwner: "test/repo"},
+				Number:    789,
+				Title:     "WIP",
+				Url:       "https://github.com/test/repo/pull/789",
+				UpdatedAt: time.Date(2024, 11, 30, 14, 0, 0, 0, time.UTC),
+				IsDraft:   false,
+				Author:    struct{ Login string }{Login: "charlie"},
+				Repository: struct {
+					NameWithOwner string
+					Url           string
+				}{NameWithOwner: "test/repo", Url: "https://github.com/test/repo"},
 				Commits: Commits{
 					Nodes: []struct {
 						Commit struct {
@@ -244,6 +297,7 @@ func TestToPullRequestItem(t *testing.T) {
 				IsDraft:        false,
 				Url:            "https://github.com/test/repo/pull/789",
 				RepositoryName: "test/repo",
+				RepositoryUrl:  "https://github.com/test/repo",
 				CheckStatus:    "FAILURE",
 			},
 		},
@@ -271,9 +325,15 @@ func TestToPullRequestItem(t *testing.T) {
 			if result.RepositoryName != tt.want.RepositoryName {
 				t.Errorf("RepositoryName: got %q, want %q", result.RepositoryName, tt.want.RepositoryName)
 			}
+			if result.RepositoryUrl != tt.want.RepositoryUrl {
+				t.Errorf("RepositoryUrl: got %q, want %q", result.RepositoryUrl, tt.want.RepositoryUrl)
+			}
 			if result.CheckStatus != tt.want.CheckStatus {
 				t.Errorf("CheckStatus: got %q, want %q", result.CheckStatus, tt.want.CheckStatus)
 			}
+			if result.CreatedAt != tt.want.CreatedAt {
+				t.Errorf("CreatedAt: got %v, want %v", result.CreatedAt, tt.want.CreatedAt)
+			}
 			if result.UpdatedAt != tt.want.UpdatedAt {
 				t.Errorf("UpdatedAt: got %v, want %v", result.UpdatedAt, tt.want.UpdatedAt)
 			}
@@ -285,6 +345,7 @@ func TestGroupAndSortPullRequests(t *testing.T) {
 	newPR := func(repo string, number int) PullRequest {
 		pr := PullRequest{Number: number}
 		pr.Repository.NameWithOwner = repo
+		pr.Repository.Url = "https://github.com/" + repo
 		return pr
 	}
 
@@ -304,6 +365,9 @@ func TestGroupAndSortPullRequests(t *testing.T) {
 	if repositories[0].Name != "org/a" {
 		t.Errorf("repositories[0].Name = %q, want %q", repositories[0].Name, "org/a")
 	}
+	if repositories[0].Url != "https://github.com/org/a" {
+		t.Errorf("repositories[0].Url = %q, want %q", repositories[0].Url, "https://github.com/org/a")
+	}
 	if repositories[1].Name != "org/b" {
 		t.Errorf("repositories[1].Name = %q, want %q", repositories[1].Name, "org/b")
 	}
@@ -322,3 +386,574 @@ func TestGroupAndSortPullRequests(t *testing.T) {
 		}
 	}
 }
+
+func TestParseSearchTarget(t *testing.T) {
+	tests := []struct {
+		arg         string
+		defaultHost string
+		want        searchTarget
+		wantErr     bool
+	}{
+		{"myorg", "", searchTarget{host: "", org: "myorg"}, false},
+		{"myorg", "ghe.corp.example", searchTarget{host: "ghe.corp.example", org: "myorg"}, false},
+		{"ghe.corp.example/platform", "", searchTarget{host: "ghe.corp.example", org: "platform"}, false},
+		{"github.com/oss-org", "ghe.corp.example", searchTarget{host: "github.com", org: "oss-org"}, false},
+		{"", "", searchTarget{}, true},
+		{"host/", "", searchTarget{}, true},
+		{"host/org/repo", "", searchTarget{}, true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseSearchTarget(tt.arg, tt.defaultHost)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseSearchTarget(%q) error = %v, wantErr %v", tt.arg, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseSearchTarget(%q) = %+v, want %+v", tt.arg, got, tt.want)
+		}
+	}
+}
+
+func TestBatchSearches(t *testing.T) {
+	searches := []*orgSearch{
+		{host: "", queryString: "org:a"},
+		{host: "ghe.corp.example", queryString: "org:b"},
+		{host: "", queryString: "org:c", done: true},
+		{host: "", queryString: "org:d"},
+		{host: "", queryString: "org:e"},
+		{host: "ghe.corp.example", queryString: "org:f"},
+	}
+
+	batches := batchSearches(searches, 2)
+
+	got := [][]string{}
+	for _, batch := range batches {
+		queryStrings := []string{}
+		for _, s := range batch {
+			queryStrings = append(queryStrings, s.queryString)
+		}
+		got = append(got, queryStrings)
+	}
+	want := [][]string{{"org:a", "org:d"}, {"org:b", "org:f"}, {"org:e"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("batchSearches() = %v, want %v", got, want)
+	}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func TestSearchPullRequestsBatch(t *testing.T) {
+	var request struct {
+		Query     string                 `json:"query"`
+		Variables map[string]interface{} `json:"variables"`
+	}
+	response := `{"data": {
+		"search0": {"issueCount": 1, "pageInfo": {"hasNextPage": false}, "nodes": [
+			{"number": 1, "title": "One", "repository": {"nameWithOwner": "a/x", "url": "https://github.com/a/x"}, "author": {"login": "alice"}, "commits": {"nodes": [{"commit": {"statusCheckRollup": {"state": "SUCCESS"}}}]}, "labels": {"nodes": [{"name": "bug"}]}}
+		]},
+		"search1": {"issueCount": 1, "pageInfo": {"hasNextPage": false}, "nodes": [
+			{"number": 2, "title": "Two", "repository": {"nameWithOwner": "b/y", "url": "https://github.com/b/y"}, "author": null, "commits": {"nodes": []}, "labels": {"nodes": []}}
+		]}
+	}}`
+	client, err := api.NewGraphQLClient(api.ClientOptions{
+		Host:      "github.com",
+		AuthToken: "token",
+		Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+			if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+				return nil, err
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": []string{"application/json"}},
+				Body:       io.NopCloser(strings.NewReader(response)),
+				Request:    r,
+			}, nil
+		}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := searchPullRequestsBatch(client, []string{"org:a", "org:b"}, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{
+		"search0: search(first: $first, after: $after0, type: ISSUE, query: $query0)",
+		"search1: search(first: $first, after: $after1, type: ISSUE, query: $query1)",
+		"$after0:String",
+	} {
+		if !strings.Contains(request.Query, want) {
+			t.Errorf("query = %q, want to contain %q", request.Query, want)
+		}
+	}
+	wantVariables := map[string]interface{}{"first": float64(20), "query0": "org:a", "query1": "org:b", "after0": nil, "after1": nil}
+	if !reflect.DeepEqual(request.Variables, wantVariables) {
+		t.Errorf("variables = %v, want %v", request.Variables, wantVariables)
+	}
+
+	if len(results) != 2 || len(results[0].repositories) != 1 || len(results[1].repositories) != 1 {
+		t.Fatalf("results = %+v, want one repository per search", results)
+	}
+	if results[0].truncated() || results[1].truncated() {
+		t.Errorf("results = %+v, want none truncated", results)
+	}
+	first := results[0].repositories[0]
+	if first.Name != "a/x" || first.Url != "https://github.com/a/x" || first.PullRequestItems[0].Author != "alice" || first.PullRequestItems[0].CheckStatus != checkStatusSuccess {
+		t.Errorf("results[0] = %+v", first)
+	}
+	if !reflect.DeepEqual(first.PullRequestItems[0].Labels, []string{"bug"}) {
+		t.Errorf("Labels = %v, want [bug]", first.PullRequestItems[0].Labels)
+	}
+	second := results[1].repositories[0]
+	if second.Name != "b/y" || second.PullRequestItems[0].Number != 2 || second.PullRequestItems[0].CheckStatus != checkStatusUnknown {
+		t.Errorf("results[1] = %+v", second)
+	}
+}
+
+// searchPage returns a search response with PRs numbered from to to-1.
+func searchPage(from, to, total int, hasNextPage bool, endCursor string) string {
+	nodes := []string{}
+	for n := from; n < to; n++ {
+		nodes = append(nodes, fmt.Sprintf(`{"number": %d, "repository": {"nameWithOwner": "a/x", "url": "https://github.com/a/x"}, "author": {"login": "alice"}, "commits": {"nodes": []}, "labels": {"nodes": []}}`, n))
+	}
+	return fmt.Sprintf(`{"data": {"search0": {"issueCount": %d, "pageInfo": {"hasNextPage": %t, "endCursor": %q}, "nodes": [%s]}}}`, total, hasNextPage, endCursor, strings.Join(nodes, ","))
+}
+
+func TestSearchPullRequestsBatchPages(t *testing.T) {
+	tests := []struct {
+		name          string
+		limit         int
+		responses     []string
+		wantVariables []map[string]interface{}
+		wantCount     int
+		wantTruncated bool
+	}{
+		{
+			name:  "all pages",
+			limit: 150,
+			responses: []string{
+				searchPage(0, 100, 120, true, "cursor1"),
+				searchPage(100, 120, 120, false, ""),
+			},
+			wantVariables: []map[string]interface{}{
+				{"first": float64(100), "query0": "org:a", "after0": nil},
+				{"first": float64(50), "query0": "org:a", "after0": "cursor1"},
+			},
+			wantCount: 120,
+		},
+		{
+			name:  "limited",
+			limit: 100,
+			responses: []string{
+				searchPage(0, 100, 120, true, "cursor1"),
+			},
+			wantVariables: []map[string]interface{}{
+				{"first": float64(100), "query0": "org:a", "after0": nil},
+			},
+			wantCount:     100,
+			wantTruncated: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := []map[string]interface{}{}
+			client, err := api.NewGraphQLClient(api.ClientOptions{
+				Host:      "github.com",
+				AuthToken: "token",
+				Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+					var request struct {
+						Variables map[string]interface{} `json:"variables"`
+					}
+					if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+						return nil, err
+					}
+					if len(requests) == len(tt.responses) {
+						return nil, fmt.Errorf("unexpected request %v", request.Variables)
+					}
+					response := tt.responses[len(requests)]
+					requests = append(requests, request.Variables)
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Header:     http.Header{"Content-Type": []string{"application/json"}},
+						Body:       io.NopCloser(strings.NewReader(response)),
+						Request:    r,
+					}, nil
+				}),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			results, err := searchPullRequestsBatch(client, []string{"org:a"}, tt.limit)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(requests, tt.wantVariables) {
+				t.Errorf("variables = %v, want %v", requests, tt.wantVariables)
+			}
+			if got := len(allPullRequests(results[0].repositories)); got != tt.wantCount {
+				t.Errorf("PRs = %d, want %d", got, tt.wantCount)
+			}
+			if got := results[0].truncated(); got != tt.wantTruncated {
+				t.Errorf("truncated() = %v, want %v", got, tt.wantTruncated)
+			}
+		})
+	}
+}
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2024, 12, 31, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		in      string
+		want    time.Time
+		wantErr bool
+	}{
+		{"2024-12-01", time.Date(2024, 12, 1, 0, 0, 0, 0, time.Local), false},
+		{"7d", startOfDay(now.AddDate(0, 0, -7)), false},
+		{"36h", startOfDay(now.Add(-36 * time.Hour)), false},
+		{"last week", time.Time{}, true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseSince(tt.in, now)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseSince(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("parseSince(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func startOfDay(t time.Time) time.Time {
+	y, m, d := t.In(time.Local).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.Local)
+}
+
+func TestPlanSearch(t *testing.T) {
+	opts := &Options{}
+
+	plan, err := planSearch("myorg", nil, opts)
+	if err != nil {
+		t.Fatalf("planSearch() error = %v", err)
+	}
+	if len(plan.queryStrings) != 1 || strings.Contains(plan.queryStrings[0], "author:") {
+		t.Errorf("planSearch() without authors = %q", plan.queryStrings)
+	}
+
+	plan, err = planSearch("myorg", []string{"alice", "bob"}, opts)
+	if err != nil {
+		t.Fatalf("planSearch() error = %v", err)
+	}
+	if len(plan.queryStrings) != 1 || !strings.HasSuffix(plan.queryStrings[0], "org:myorg author:alice author:bob") {
+		t.Errorf("planSearch() = %q, want one query with both authors", plan.queryStrings)
+	}
+
+	authors := []string{}
+	for i := 0; i < 30; i++ {
+		authors = append(authors, fmt.Sprintf("member-%02d", i))
+	}
+	plan, err = planSearch("myorg", authors, opts)
+	if err != nil {
+		t.Fatalf("planSearch() error = %v", err)
+	}
+	if len(plan.queryStrings) < 2 {
+		t.Fatalf("planSearch() = %q, want several queries", plan.queryStrings)
+	}
+	count := 0
+	for _, queryString := range plan.queryStrings {
+		if len(queryString) > maxQueryLength {
+			t.Errorf("len(%q) = %d, want at most %d", queryString, len(queryString), maxQueryLength)
+		}
+		count += strings.Count(queryString, "author:")
+	}
+	if count != len(authors) {
+		t.Errorf("author qualifiers = %d, want %d", count, len(authors))
+	}
+}
+
+func TestPlanSearchOverflowingExcludes(t *testing.T) {
+	opts := &Options{Excludes: []string{"*-archive"}}
+	for i := 0; i < 20; i++ {
+		opts.Excludes = append(opts.Excludes, fmt.Sprintf("repository-%02d", i))
+	}
+
+	plan, err := planSearch("myorg", []string{"alice", "bob"}, opts)
+	if err != nil {
+		t.Fatalf("planSearch() error = %v", err)
+	}
+	if len(plan.excludes) == 0 {
+		t.Fatalf("planSearch() excludes = %q, want the excludes that do not fit", plan.excludes)
+	}
+
+	for _, queryString := range plan.queryStrings {
+		if len(queryString) > maxQueryLength {
+			t.Errorf("len(%q) = %d, want at most %d", queryString, len(queryString), maxQueryLength)
+		}
+		if n := countQueryOperators(queryString); n > maxQueryOperators {
+			t.Errorf("query %q has %d operators, want at most %d", queryString, n, maxQueryOperators)
+		}
+		if strings.Contains(queryString, "*-archive") {
+			t.Errorf("query %q contains a pattern", queryString)
+		}
+		if inQuery := strings.Count(queryString, "-repo:"); inQuery+len(plan.excludes) != 20 {
+			t.Errorf("excludes in query %q = %d, client-side = %d, want 20 in total", queryString, inQuery, len(plan.excludes))
+		}
+	}
+	for _, name := range plan.excludes {
+		if !strings.HasPrefix(name, "myorg/repository-") {
+			t.Errorf("client-side exclude = %q, want myorg/repository-NN", name)
+		}
+	}
+}
+
+func TestPlanSearchIncludedRepositories(t *testing.T) {
+	opts := &Options{IncludeRepos: []string{"api", "myorg/web", "other/cli", "legacy"}, Excludes: []string{"legacy"}}
+	plan, err := planSearch("myorg", []string{"alice"}, opts)
+	if err != nil {
+		t.Fatalf("planSearch() error = %v", err)
+	}
+	want := []string{"is:open is:pr archived:false repo:myorg/api repo:myorg/web author:alice"}
+	if !reflect.DeepEqual(plan.queryStrings, want) {
+		t.Errorf("planSearch() = %q, want %q", plan.queryStrings, want)
+	}
+
+	opts.IncludeRepos = append(opts.IncludeRepos, "*-service")
+	plan, err = planSearch("myorg", []string{"alice"}, opts)
+	if err != nil {
+		t.Fatalf("planSearch() error = %v", err)
+	}
+	want = []string{
+		"is:open is:pr archived:false org:myorg -repo:myorg/legacy author:alice",
+		"is:open is:pr archived:false repo:myorg/api repo:myorg/web author:alice",
+	}
+	if !reflect.DeepEqual(plan.queryStrings, want) {
+		t.Errorf("planSearch() with a pattern = %q, want %q", plan.queryStrings, want)
+	}
+
+	opts = &Options{}
+	for i := 0; i < 30; i++ {
+		opts.IncludeRepos = append(opts.IncludeRepos, fmt.Sprintf("repository-%02d", i))
+	}
+	plan, err = planSearch("myorg", []string{"alice", "bob"}, opts)
+	if err != nil {
+		t.Fatalf("planSearch() error = %v", err)
+	}
+	count := 0
+	for _, queryString := range plan.queryStrings {
+		if len(queryString) > maxQueryLength {
+			t.Errorf("len(%q) = %d, want at most %d", queryString, len(queryString), maxQueryLength)
+		}
+		if !strings.HasSuffix(queryString, " author:alice author:bob") || strings.Contains(queryString, "org:") {
+			t.Errorf("query %q, want repositories and both authors", queryString)
+		}
+		count += strings.Count(queryString, "repo:")
+	}
+	if count != 30 {
+		t.Errorf("repo qualifiers = %d, want 30", count)
+	}
+}
+
+func TestPlanSearchSplitsAdditionalQueries(t *testing.T) {
+	labels := []string{}
+	for _, label := range []string{"a", "b", "c", "d", "e", "f", "g"} {
+		labels = append(labels, "label:"+label)
+	}
+	long := []string{}
+	for i := 0; i < 30; i++ {
+		long = append(long, fmt.Sprintf("label:component-%02d", i))
+	}
+
+	tests := []struct {
+		name    string
+		queries []string
+		authors []string
+		want    []string
+	}{
+		{"too many operators", []string{strings.Join(labels, " OR ")}, nil, labels},
+		{"too long", []string{strings.Join(long, " OR ")}, []string{"alice", "bob"}, long},
+		{"with another value", []string{"review:required", strings.Join(labels, " OR "), "draft:false"}, nil, labels},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, err := planSearch("myorg", tt.authors, &Options{AdditionalQueries: tt.queries})
+			if err != nil {
+				t.Fatalf("planSearch() error = %v", err)
+			}
+			if len(plan.queryStrings) < 2 {
+				t.Fatalf("planSearch() = %q, want several queries", plan.queryStrings)
+			}
+			count := 0
+			for _, queryString := range plan.queryStrings {
+				if len(queryString) > maxQueryLength {
+					t.Errorf("len(%q) = %d, want at most %d", queryString, len(queryString), maxQueryLength)
+				}
+				if n := countQueryOperators(queryString); n > maxQueryOperators {
+					t.Errorf("query %q has %d operators, want at most %d", queryString, n, maxQueryOperators)
+				}
+				for _, query := range tt.queries {
+					if len(orTerms(query)) == 1 && !strings.Contains(queryString, query) {
+						t.Errorf("query %q, want to contain %q", queryString, query)
+					}
+				}
+				for _, author := range tt.authors {
+					if !strings.Contains(queryString, "author:"+author) {
+						t.Errorf("query %q, want to contain author:%s", queryString, author)
+					}
+				}
+				count += strings.Count(queryString, "label:")
+			}
+			if count != len(tt.want) {
+				t.Errorf("label qualifiers = %d, want each of the %d alternatives once", count, len(tt.want))
+			}
+		})
+	}
+}
+
+func TestPlanSearchInvalidQuery(t *testing.T) {
+	opts := &Options{AdditionalQueries: []string{"label:a AND label:b AND label:c AND label:d AND label:e AND label:f AND label:g"}}
+	if _, err := planSearch("myorg", nil, opts); err == nil || !strings.Contains(err.Error(), "operators") {
+		t.Errorf("planSearch() error = %v, want too many operators", err)
+	}
+
+	opts = &Options{AdditionalQueries: []string{strings.Repeat("x", maxQueryLength)}}
+	if _, err := planSearch("myorg", nil, opts); err == nil || !strings.Contains(err.Error(), "characters long") {
+		t.Errorf("planSearch() error = %v, want too long", err)
+	}
+
+	opts = &Options{AdditionalQueries: []string{"label:a OR " + strings.Repeat("x", maxQueryLength)}}
+	if _, err := planSearch("myorg", nil, opts); err == nil || !strings.Contains(err.Error(), "characters long") {
+		t.Errorf("planSearch() error = %v, want an alternative too long", err)
+	}
+}
+
+func TestOrTerms(t *testing.T) {
+	tests := map[string][]string{
+		"label:a OR label:b":                 {"label:a", "label:b"},
+		`label:"needs review" OR draft:true`: {`label:"needs review"`, "draft:true"},
+		"(label:a label:b) OR label:c":       {"(label:a label:b)", "label:c"},
+		"label:a label:b OR label:c":         {"label:a label:b OR label:c"},
+		"label:a OR label:b AND label:c":     {"label:a OR label:b AND label:c"},
+		"(label:a OR label:b)":               {"(label:a OR label:b)"},
+		`"a OR b"`:                           {`"a OR b"`},
+		"label:a":                            {"label:a"},
+	}
+
+	for query, want := range tests {
+		if got := orTerms(query); !reflect.DeepEqual(got, want) {
+			t.Errorf("orTerms(%q) = %q, want %q", query, got, want)
+		}
+	}
+}
+
+func TestCountQueryOperators(t *testing.T) {
+	tests := map[string]int{
+		"is:pr org:myorg":                         0,
+		"label:a OR label:b":                      1,
+		"NOT draft AND label:a OR label:b":        3,
+		`"this OR that" in:title OR label:x`:      1,
+		`"NOT" label:a`:                           0,
+		"or and not label:OR":                     0,
+		`"fix AND feature" NOT "wip OR draft" OR`: 2,
+	}
+
+	for queryString, want := range tests {
+		if got := countQueryOperators(queryString); got != want {
+			t.Errorf("countQueryOperators(%q) = %d, want %d", queryString, got, want)
+		}
+	}
+}
+
+func TestSplitQualifiers(t *testing.T) {
+	tests := []struct {
+		qualifiers []string
+		want       []string
+	}{
+		{nil, []string{"base"}},
+		{[]string{"a:1", "b:2"}, []string{"base a:1 b:2"}},
+		{[]string{"a:1", "b:2", "c:3"}, []string{"base a:1 b:2", "base c:3"}},
+		{[]string{"long:qualifier", "d:4"}, []string{"base long:qualifier", "base d:4"}},
+	}
+
+	for _, tt := range tests {
+		got := splitQualifiers("base", tt.qualifiers, 12)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitQualifiers(%q) = %q, want %q", tt.qualifiers, got, tt.want)
+		}
+	}
+}
+
+func TestMergeSearchResults(t *testing.T) {
+	base := time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC)
+	pr := func(number int) PullRequestItem {
+		return PullRequestItem{Number: number, RepositoryName: "org/a", Url: fmt.Sprintf("https://github.com/org/a/pull/%d", number), CreatedAt: base.Add(time.Duration(number) * time.Hour)}
+	}
+	result := func(total int, numbers ...int) searchResult {
+		items := []PullRequestItem{}
+		for _, n := range numbers {
+			items = append(items, pr(n))
+		}
+		return searchResult{repositories: groupPullRequestItems(items), total: total}
+	}
+
+	tests := []struct {
+		name          string
+		results       []searchResult
+		want          []int
+		wantTruncated bool
+	}{
+		{"within the limit", []searchResult{result(2, 1, 2), result(2, 2, 3)}, []int{3, 2, 1}, false},
+		{"over the limit together", []searchResult{result(3, 1, 2, 3), result(3, 4, 5, 6)}, []int{6, 5, 4}, true},
+		{"truncated search", []searchResult{result(10, 1, 2)}, []int{2, 1}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repositories, truncated := mergeSearchResults(tt.results, 3)
+			got := []int{}
+			for _, pr := range allPullRequests(repositories) {
+				got = append(got, pr.Number)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("mergeSearchResults() = %v, want %v", got, tt.want)
+			}
+			if truncated != tt.wantTruncated {
+				t.Errorf("truncated = %v, want %v", truncated, tt.wantTruncated)
+			}
+		})
+	}
+}
+
+func TestMergeRepositories(t *testing.T) {
+	pr := func(repo string, number int) PullRequestItem {
+		return PullRequestItem{Number: number, RepositoryName: repo, Url: fmt.Sprintf("https://github.com/%s/pull/%d", repo, number)}
+	}
+	repositories := []RepositoryItem{
+		{Name: "org/b", PullRequestItems: []PullRequestItem{pr("org/b", 1)}},
+		{Name: "org/a", PullRequestItems: []PullRequestItem{pr("org/a", 2)}},
+		{Name: "org/a", PullRequestItems: []PullRequestItem{pr("org/a", 3), pr("org/a", 2)}},
+	}
+
+	merged := mergeRepositories(repositories)
+
+	if len(merged) != 2 || merged[0].Name != "org/a" || merged[1].Name != "org/b" {
+		t.Fatalf("mergeRepositories() = %+v, want org/a and org/b", merged)
+	}
+	gotNumbers := []int{}
+	for _, pr := range merged[0].PullRequestItems {
+		gotNumbers = append(gotNumbers, pr.Number)
+	}
+	if !reflect.DeepEqual(gotNumbers, []int{3, 2}) {
+		t.Errorf("org/a numbers = %v, want [3 2]", gotNumbers)
+	}
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+type draftFilter int
+
+const (
+	draftAny draftFilter = iota
+	draftOnly
+	draftExclude
+)
+
+// reviewDecisionNone stands for pull requests without a review decision.
+const reviewDecisionNone = "NONE"
+
+var checkStatusCycle = []string{"", checkStatusFailure, checkStatusPending, checkStatusSuccess}
+
+var reviewDecisionCycle = []string{"", reviewDecisionApproved, reviewDecisionChangesRequested, reviewDecisionReviewRequired}
+
+// localFilter narrows the loaded pull requests without searching again.
+// It is written and displayed with a subset of the search syntax, e.g.
+// "author:alice repo:api status:failure is:draft".
+type localFilter struct {
+	authors     []string
+	repos       []string
+	checkStatus string
+	review      string
+	draft       draftFilter
+	mine        bool
+	words       []string
+}
+
+func parseLocalFilter(query string) (localFilter, error) {
+	f := localFilter{}
+	for _, token := range strings.Fields(query) {
+		name, value, ok := strings.Cut(token, ":")
+		if !ok {
+			f.words = append(f.words, token)
+			continue
+		}
+
+		switch strings.ToLower(name) {
+		case "author":
+			f.authors = append(f.authors, value)
+		case "repo":
+			f.repos = append(f.repos, value)
+		case "status":
+			status := strings.ToUpper(value)
+			switch status {
+			case checkStatusSuccess, checkStatusFailure, checkStatusPending, checkStatusUnknown:
+				f.checkStatus = status
+			default:
+				return localFilter{}, fmt.Errorf("unknown status %q", value)
+			}
+		case "review":
+			review := strings.ToUpper(value)
+			switch review {
+			case reviewDecisionApproved, reviewDecisionChangesRequested, reviewDecisionReviewRequired, reviewDecisionNone:
+				f.review = review
+			default:
+				return localFilter{}, fmt.Errorf("unknown review decision %q", value)
+			}
+		case "is":
+			switch strings.ToLower(value) {
+			case "draft":
+				f.draft = draftOnly
+			case "ready":
+				f.draft = draftExclude
+			case "mine":
+				f.mine = true
+			default:
+				return localFilter{}, fmt.Errorf("unknown qualifier %q", token)
+			}
+		default:
+			return localFilter{}, fmt.Errorf("unknown qualifier %q", token)
+		}
+	}
+	return f, nil
+}
+
+func (f localFilter) String() string {
+	tokens := []string{}
+	for _, author := range f.authors {
+		tokens = append(tokens, "author:"+author)
+	}
+	for _, repo := range f.repos {
+		tokens = append(tokens, "repo:"+repo)
+	}
+	if f.checkStatus != "" {
+		tokens = append(tokens, "status:"+strings.ToLower(f.checkStatus))
+	}
+	if f.review != "" {
+		tokens = append(tokens, "review:"+strings.ToLower(f.review))
+	}
+	switch f.draft {
+	case draftOnly:
+		tokens = append(tokens, "is:draft")
+	case draftExclude:
+		tokens = append(tokens, "is:ready")
+	}
+	if f.mine {
+		tokens = append(tokens, "is:mine")
+	}
+	tokens = append(tokens, f.words...)
+	return strings.Join(tokens, " ")
+}
+
+// matches reports whether pri passes the filter. viewer is the login of
+// the current user, used by "is:mine".
+func (f localFilter) matches(pri *PullRequestItem, viewer string) bool {
+	if len(f.authors) > 0 && !containsFold(f.authors, pri.Author) {
+		return false
+	}
+	if len(f.repos) > 0 && !f.matchesRepo(pri.RepositoryName) {
+		return false
+	}
+	if f.checkStatus != "" && pri.CheckStatus != f.checkStatus {
+		return false
+	}
+	if f.review != "" && reviewDecisionOf(pri) != f.review {
+		return false
+	}
+	if f.draft == draftOnly && !pri.IsDraft || f.draft == draftExclude && pri.IsDraft {
+		return false
+	}
+	if f.mine && !strings.EqualFold(pri.Author, viewer) {
+		return false
+	}
+	title := strings.ToLower(pri.Title)
+	for _, word := range f.words {
+		if !strings.Contains(title, strings.ToLower(word)) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesRepo matches "owner/repo" exactly and a bare "repo" against the
+// repository part of the name.
+func (f localFilter) matchesRepo(name string) bool {
+	_, repo, _ := strings.Cut(name, "/")
+	for _, r := range f.repos {
+		if strings.EqualFold(r, name) || strings.EqualFold(r, repo) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *localFilter) cycleDraft() {
+	f.draft = (f.draft + 1) % 3
+}
+
+func (f *localFilter) cycleCheckStatus() {
+	f.checkStatus = nextInCycle(checkStatusCycle, f.checkStatus)
+}
+
+func (f *localFilter) cycleReview() {
+	f.review = nextInCycle(reviewDecisionCycle, f.review)
+}
+
+func reviewDecisionOf(pri *PullRequestItem) string {
+	if pri.ReviewDecision == "" {
+		return reviewDecisionNone
+	}
+	return pri.ReviewDecision
+}
+
+func nextInCycle(cycle []string, current string) string {
+	for i, v := range cycle {
+		if v == current {
+			return cycle[(i+1)%len(cycle)]
+		}
+	}
+	return cycle[0]
+}
+
+func containsFold(values []string, s string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
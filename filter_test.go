@@ -0,0 +1,138 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestParseLocalFilter(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "empty query",
+			query: "",
+			want:  "",
+		},
+		{
+			name:  "qualifiers and words",
+			query: "author:alice repo:api status:FAILURE review:approved is:draft is:mine fix",
+			want:  "author:alice repo:api status:failure review:approved is:draft is:mine fix",
+		},
+		{
+			name:  "ready",
+			query: "is:ready",
+			want:  "is:ready",
+		},
+		{
+			name:    "unknown status",
+			query:   "status:green",
+			wantErr: true,
+		},
+		{
+			name:    "unknown review decision",
+			query:   "review:lgtm",
+			wantErr: true,
+		},
+		{
+			name:    "unknown qualifier",
+			query:   "label:bug",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := parseLocalFilter(tt.query)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("parseLocalFilter(%q) returned no error", tt.query)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseLocalFilter(%q) returned error: %v", tt.query, err)
+			}
+			if result.String() != tt.want {
+				t.Errorf("parseLocalFilter(%q).String() = %q, want %q", tt.query, result.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestLocalFilterMatches(t *testing.T) {
+	pri := &PullRequestItem{
+		Title:          "Fix login bug",
+		Author:         "alice",
+		RepositoryName: "org/api",
+		CheckStatus:    checkStatusFailure,
+		IsDraft:        true,
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		want  bool
+	}{
+		{name: "empty filter", query: "", want: true},
+		{name: "author matches", query: "author:Alice", want: true},
+		{name: "author differs", query: "author:bob", want: false},
+		{name: "any of several authors", query: "author:bob author:alice", want: true},
+		{name: "bare repo name", query: "repo:api", want: true},
+		{name: "full repo name", query: "repo:org/api", want: true},
+		{name: "repo differs", query: "repo:web", want: false},
+		{name: "status matches", query: "status:failure", want: true},
+		{name: "status differs", query: "status:success", want: false},
+		{name: "no review decision", query: "review:none", want: true},
+		{name: "review differs", query: "review:approved", want: false},
+		{name: "draft", query: "is:draft", want: true},
+		{name: "ready", query: "is:ready", want: false},
+		{name: "mine", query: "is:mine", want: true},
+		{name: "title words", query: "LOGIN fix", want: true},
+		{name: "title word differs", query: "feature", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := parseLocalFilter(tt.query)
+			if err != nil {
+				t.Fatalf("parseLocalFilter(%q) returned error: %v", tt.query, err)
+			}
+			if result := f.matches(pri, "alice"); result != tt.want {
+				t.Errorf("matches() with %q = %v, want %v", tt.query, result, tt.want)
+			}
+		})
+	}
+}
+
+func TestLocalFilterCycles(t *testing.T) {
+	f := localFilter{}
+
+	gotStatuses := []string{}
+	for range checkStatusCycle {
+		f.cycleCheckStatus()
+		gotStatuses = append(gotStatuses, f.checkStatus)
+	}
+	wantStatuses := []string{checkStatusFailure, checkStatusPending, checkStatusSuccess, ""}
+	for i := range wantStatuses {
+		if gotStatuses[i] != wantStatuses[i] {
+			t.Errorf("check statuses = %v, want %v", gotStatuses, wantStatuses)
+			break
+		}
+	}
+
+	gotDrafts := []draftFilter{}
+	for range 3 {
+		f.cycleDraft()
+		gotDrafts = append(gotDrafts, f.draft)
+	}
+	wantDrafts := []draftFilter{draftOnly, draftExclude, draftAny}
+	for i := range wantDrafts {
+		if gotDrafts[i] != wantDrafts[i] {
+			t.Errorf("drafts = %v, want %v", gotDrafts, wantDrafts)
+			break
+		}
+	}
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/logrusorgru/aurora/v4"
@@ -11,42 +12,85 @@ type Formatter interface {
 	FormatPRNumber(pri *PullRequestItem) string
 	FormatAuthor(pri *PullRequestItem) string
 	FormatUpdatedAt(pri *PullRequestItem) string
+	FormatCreatedAt(pri *PullRequestItem) string
+	FormatMergedAt(pri *PullRequestItem) string
+	FormatState(pri *PullRequestItem) string
 	FormatTitle(pri *PullRequestItem) string
 	FormatCheckStatus(pri *PullRequestItem) string
 	FormatReviewDecision(pri *PullRequestItem) string
-	FormatRepositoryName(name string) string
+	FormatLabels(pri *PullRequestItem) string
+	FormatSize(pri *PullRequestItem) string
+	FormatRepositoryName(name string, url string) string
 }
 
-type ColorFormatter struct{}
+type ColorFormatter struct {
+	theme *Theme
+}
+
+func paint(color uint8, arg interface{}) aurora.Value {
+	return aurora.Index(aurora.ColorIndex(color), arg)
+}
+
+func (cf *ColorFormatter) colors() Theme {
+	if cf.theme == nil {
+		return defaultTheme()
+	}
+	return *cf.theme
+}
 
 func (cf *ColorFormatter) FormatPRNumber(pri *PullRequestItem) string {
 	num := fmt.Sprintf("#%d", pri.Number)
 	if pri.IsDraft {
-		return aurora.Gray(8, aurora.Magenta(num).Bold().Hyperlink(pri.Url)).String()
+		return paint(cf.colors().Draft, paint(cf.colors().Number, num).Bold().Hyperlink(pri.Url)).String()
 	}
-	return aurora.Magenta(num).Bold().Hyperlink(pri.Url).String()
+	return paint(cf.colors().Number, num).Bold().Hyperlink(pri.Url).String()
 }
 
 func (cf *ColorFormatter) FormatAuthor(pri *PullRequestItem) string {
 	if pri.IsDraft {
-		return aurora.Gray(8, pri.Author).String()
+		return paint(cf.colors().Draft, pri.Author).String()
 	}
-	return aurora.Green(pri.Author).String()
+	return paint(cf.colors().Author, pri.Author).String()
 }
 
 func (cf *ColorFormatter) FormatUpdatedAt(pri *PullRequestItem) string {
 	updatedAt := pri.UpdatedAt.In(time.Local).Format("2006-01-02")
 	if pri.IsDraft {
-		return aurora.Gray(8, updatedAt).String()
+		return paint(cf.colors().Draft, updatedAt).String()
 	}
 	return updatedAt
 }
 
+func (cf *ColorFormatter) FormatCreatedAt(pri *PullRequestItem) string {
+	createdAt := pri.CreatedAt.In(time.Local).Format("2006-01-02")
+	if pri.IsDraft {
+		return paint(cf.colors().Draft, createdAt).String()
+	}
+	return createdAt
+}
+
+func (cf *ColorFormatter) FormatMergedAt(pri *PullRequestItem) string {
+	return formatMergedAt(pri)
+}
+
+func (cf *ColorFormatter) FormatState(pri *PullRequestItem) string {
+	switch pri.State {
+	case pullRequestStateOpen:
+		return paint(cf.colors().Success, formatState(pri)).String()
+	case pullRequestStateClosed:
+		return paint(cf.colors().Failure, formatState(pri)).String()
+	case pullRequestStateMerged:
+		return paint(cf.colors().Approved, formatState(pri)).String()
+	default:
+		return formatState(pri)
+	}
+}
+
 func (cf *ColorFormatter) FormatTitle(pri *PullRequestItem) string {
 	title := pri.Title
 	if pri.IsDraft {
 		title = title + " (draft)"
-		return aurora.Gray(8, title).String()
+		return paint(cf.colors().Draft, title).String()
 	}
 	return title
 }
@@ -54,9 +98,9 @@ func (cf *ColorFormatter) FormatTitle(pri *PullRequestItem) string {
 func (cf *ColorFormatter) FormatCheckStatus(pri *PullRequestItem) string {
 	switch pri.CheckStatus {
 	case checkStatusSuccess:
-		return aurora.Green("✔").String()
+		return paint(cf.colors().Success, "✔").String()
 	case checkStatusFailure:
-		return aurora.Red("✘").String()
+		return paint(cf.colors().Failure, "✘").String()
 	case checkStatusPending:
 		return "⏳"
 	default:
@@ -66,14 +110,27 @@ func (cf *ColorFormatter) FormatCheckStatus(pri *PullRequestItem) string {
 
 func (cf *ColorFormatter) FormatReviewDecision(pri *PullRequestItem) string {
 	if pri.ReviewDecision == reviewDecisionApproved {
-		return aurora.Magenta("✓").String()
+		return paint(cf.colors().Approved, "✓").String()
 	}
 	return ""
 }
 
-func (cf *ColorFormatter) FormatRepositoryName(name string) string {
-	repoLink := fmt.Sprintf("https://github.com/%s", name)
-	return aurora.Hyperlink(name, repoLink).String()
+func (cf *ColorFormatter) FormatLabels(pri *PullRequestItem) string {
+	labels := strings.Join(pri.Labels, ",")
+	if pri.IsDraft {
+		return paint(cf.colors().Draft, labels).String()
+	}
+	return labels
+}
+
+func (cf *ColorFormatter) FormatSize(pri *PullRequestItem) string {
+	additions := paint(cf.colors().Success, fmt.Sprintf("+%d", pri.Additions))
+	deletions := paint(cf.colors().Failure, fmt.Sprintf("-%d", pri.Deletions))
+	return fmt.Sprintf("%s %s", additions, deletions)
+}
+
+func (cf *ColorFormatter) FormatRepositoryName(name string, url string) string {
+	return aurora.Hyperlink(name, url).String()
 }
 
 type NoColorFormatter struct{}
@@ -90,6 +147,18 @@ func (ncf *NoColorFormatter) FormatUpdatedAt(pri *PullRequestItem) string {
 	return pri.UpdatedAt.In(time.Local).Format("2006-01-02")
 }
 
+func (ncf *NoColorFormatter) FormatCreatedAt(pri *PullRequestItem) string {
+	return pri.CreatedAt.In(time.Local).Format("2006-01-02")
+}
+
+func (ncf *NoColorFormatter) FormatMergedAt(pri *PullRequestItem) string {
+	return formatMergedAt(pri)
+}
+
+func (ncf *NoColorFormatter) FormatState(pri *PullRequestItem) string {
+	return formatState(pri)
+}
+
 func (ncf *NoColorFormatter) FormatTitle(pri *PullRequestItem) string {
 	title := pri.Title
 	if pri.IsDraft {
@@ -118,13 +187,34 @@ func (ncf *NoColorFormatter) FormatReviewDecision(pri *PullRequestItem) string {
 	return ""
 }
 
-func (ncf *NoColorFormatter) FormatRepositoryName(name string) string {
+func (ncf *NoColorFormatter) FormatLabels(pri *PullRequestItem) string {
+	return strings.Join(pri.Labels, ",")
+}
+
+func (ncf *NoColorFormatter) FormatSize(pri *PullRequestItem) string {
+	return fmt.Sprintf("+%d -%d", pri.Additions, pri.Deletions)
+}
+
+func (ncf *NoColorFormatter) FormatRepositoryName(name string, url string) string {
 	return name
 }
 
-func NewFormatter(noColor bool) Formatter {
+func formatMergedAt(pri *PullRequestItem) string {
+	if pri.MergedAt.IsZero() {
+		return ""
+	}
+	return pri.MergedAt.In(time.Local).Format("2006-01-02")
+}
+
+func formatState(pri *PullRequestItem) string {
+	return strings.ToLower(pri.State)
+}
+
+// NewFormatter returns a formatter for the output mode. A nil theme uses
+// the default colors.
+func NewFormatter(noColor bool, theme *Theme) Formatter {
 	if noColor {
 		return &NoColorFormatter{}
 	}
-	return &ColorFormatter{}
+	return &ColorFormatter{theme: theme}
 }
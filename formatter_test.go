@@ -245,20 +245,29 @@ func TestColorFormatterFormatRepositoryName(t *testing.T) {
 	tests := []struct {
 		name         string
 		repoName     string
+		repoUrl      string
 		wantRepoName string
 		wantLink     string
 	}{
 		{
 			name:         "format repository name",
 			repoName:     "test/repo",
+			repoUrl:      "https://github.com/test/repo",
 			wantRepoName: "test/repo",
 			wantLink:     "https://github.com/test/repo",
 		},
+		{
+			name:         "enterprise server repository",
+			repoName:     "platform/api",
+			repoUrl:      "https://ghe.corp.example/platform/api",
+			wantRepoName: "platform/api",
+			wantLink:     "https://ghe.corp.example/platform/api",
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := cf.FormatRepositoryName(tt.repoName)
+			result := cf.FormatRepositoryName(tt.repoName, tt.repoUrl)
 			if !strings.Contains(result, tt.wantRepoName) {
 				t.Errorf("FormatRepositoryName() = %q, want to contain %q", result, tt.wantRepoName)
 			}
@@ -303,6 +312,57 @@ func TestNoColorFormatterFormatUpdatedAt(t *testing.T) {
 	}
 }
 
+func TestNoColorFormatterFormatCreatedAt(t *testing.T) {
+	ncf := &NoColorFormatter{}
+	testTime := time.Date(2024, 11, 30, 12, 0, 0, 0, time.UTC)
+	pri := &PullRequestItem{CreatedAt: testTime}
+	result := ncf.FormatCreatedAt(pri)
+
+	expectedDate := testTime.In(time.Local).Format("2006-01-02")
+	if result != expectedDate {
+		t.Errorf("FormatCreatedAt() = %q, want %q", result, expectedDate)
+	}
+}
+
+func TestNoColorFormatterFormatLabels(t *testing.T) {
+	ncf := &NoColorFormatter{}
+	pri := &PullRequestItem{Labels: []string{"bug", "good first issue"}}
+	if result := ncf.FormatLabels(pri); result != "bug,good first issue" {
+		t.Errorf("FormatLabels() = %q, want %q", result, "bug,good first issue")
+	}
+}
+
+func TestNoColorFormatterFormatSize(t *testing.T) {
+	ncf := &NoColorFormatter{}
+	pri := &PullRequestItem{Additions: 120, Deletions: 4}
+	if result := ncf.FormatSize(pri); result != "+120 -4" {
+		t.Errorf("FormatSize() = %q, want %q", result, "+120 -4")
+	}
+}
+
+func TestNoColorFormatterFormatState(t *testing.T) {
+	ncf := &NoColorFormatter{}
+	mergedAt := time.Date(2024, 11, 30, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		pri        *PullRequestItem
+		wantState  string
+		wantMerged string
+	}{
+		{&PullRequestItem{State: pullRequestStateOpen}, "open", ""},
+		{&PullRequestItem{State: pullRequestStateClosed}, "closed", ""},
+		{&PullRequestItem{State: pullRequestStateMerged, MergedAt: mergedAt}, "merged", mergedAt.In(time.Local).Format("2006-01-02")},
+	}
+
+	for _, tt := range tests {
+		if got := ncf.FormatState(tt.pri); got != tt.wantState {
+			t.Errorf("FormatState() = %q, want %q", got, tt.wantState)
+		}
+		if got := ncf.FormatMergedAt(tt.pri); got != tt.wantMerged {
+			t.Errorf("FormatMergedAt() = %q, want %q", got, tt.wantMerged)
+		}
+	}
+}
+
 func TestNoColorFormatterFormatTitle(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -416,7 +476,7 @@ func TestNoColorFormatterFormatReviewDecision(t *testing.T) {
 func TestNoColorFormatterFormatRepositoryName(t *testing.T) {
 	ncf := &NoColorFormatter{}
 	repoName := "test/repo"
-	result := ncf.FormatRepositoryName(repoName)
+	result := ncf.FormatRepositoryName(repoName, "https://github.com/test/repo")
 
 	if result != repoName {
 		t.Errorf("FormatRepositoryName() = %q, want %q", result, repoName)
@@ -443,7 +503,7 @@ func TestNewFormatter(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := NewFormatter(tt.noColor)
+			result := NewFormatter(tt.noColor, nil)
 			// Check type instead of equality since we can't compare pointers directly
 			switch v := result.(type) {
 			case *ColorFormatter:
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// exitCodeGateFailed is the exit code when a --fail-if condition matches.
+// Other errors exit with 1.
+const exitCodeGateFailed = 2
+
+// gateError reports the --fail-if conditions that matched.
+type gateError struct {
+	matched []string
+}
+
+func (e *gateError) Error() string {
+	return fmt.Sprintf("fail-if matched: %s", strings.Join(e.matched, ", "))
+}
+
+var gateKinds = map[string]func(pri *PullRequestItem) bool{
+	"count":   func(pri *PullRequestItem) bool { return true },
+	"failing": func(pri *PullRequestItem) bool { return pri.CheckStatus == checkStatusFailure },
+	"pending": func(pri *PullRequestItem) bool { return pri.CheckStatus == checkStatusPending },
+	"drafts":  func(pri *PullRequestItem) bool { return pri.IsDraft },
+	"approved": func(pri *PullRequestItem) bool {
+		return pri.ReviewDecision == reviewDecisionApproved
+	},
+	"changes_requested": func(pri *PullRequestItem) bool {
+		return pri.ReviewDecision == reviewDecisionChangesRequested
+	},
+}
+
+var gateConditionPattern = regexp.MustCompile(`^([a-z_]+)(?:\.(age|stale))?\s*(>=|<=|==|!=|>|<|=)\s*(\S+)$`)
+
+// gateCondition is a --fail-if expression.
+//
+// "<kind><op><n>" compares the number of pull requests of a kind, e.g.
+// "count>0" or "failing>3". "[<kind>.]age<op><duration>" and
+// "[<kind>.]stale<op><duration>" compare the largest time since creation or
+// last update among them, e.g. "stale>14d" or "failing.age>7d".
+type gateCondition struct {
+	expr     string
+	kind     string
+	measure  string
+	op       string
+	count    int
+	duration time.Duration
+}
+
+func parseGateCondition(expr string) (gateCondition, error) {
+	m := gateConditionPattern.FindStringSubmatch(strings.TrimSpace(expr))
+	if m == nil {
+		return gateCondition{}, fmt.Errorf("invalid fail-if condition: %s", expr)
+	}
+	c := gateCondition{expr: expr, kind: m[1], measure: m[2], op: m[3]}
+
+	if c.kind == "age" || c.kind == "stale" {
+		c.kind, c.measure = "count", c.kind
+	}
+	if _, ok := gateKinds[c.kind]; !ok {
+		return gateCondition{}, fmt.Errorf("invalid fail-if condition: %s: unknown kind %q", expr, c.kind)
+	}
+
+	var err error
+	if c.measure == "" {
+		c.count, err = strconv.Atoi(m[4])
+	} else {
+		c.duration, err = parseAge(m[4])
+	}
+	if err != nil {
+		return gateCondition{}, fmt.Errorf("invalid fail-if condition: %s: %w", expr, err)
+	}
+	return c, nil
+}
+
+func parseGateConditions(exprs []string) ([]gateCondition, error) {
+	conditions := make([]gateCondition, 0, len(exprs))
+	for _, expr := range exprs {
+		c, err := parseGateCondition(expr)
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, c)
+	}
+	return conditions, nil
+}
+
+// parseAge parses durations in days ("14d") and weeks ("2w") in addition
+// to the units of time.ParseDuration.
+func parseAge(s string) (time.Duration, error) {
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if n, ok := strings.CutSuffix(s, suffix); ok {
+			v, err := strconv.ParseFloat(n, 64)
+			if err != nil {
+				return 0, fmt.Errorf("invalid duration %q", s)
+			}
+			return time.Duration(v * float64(unit)), nil
+		}
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	return d, nil
+}
+
+func compare[T int | time.Duration](a T, op string, b T) bool {
+	switch op {
+	case ">":
+		return a > b
+	case ">=":
+		return a >= b
+	case "<":
+		return a < b
+	case "<=":
+		return a <= b
+	case "!=":
+		return a != b
+	default:
+		return a == b
+	}
+}
+
+// evaluate reports whether the condition holds for repositories, and the
+// value it compared. Age conditions never hold when no PR is of the kind.
+func (c gateCondition) evaluate(repositories []RepositoryItem, now time.Time) (bool, string) {
+	match := gateKinds[c.kind]
+	count := 0
+	var oldest time.Duration
+	for _, repo := range repositories {
+		for _, pr := range repo.PullRequestItems {
+			if !match(&pr) {
+				continue
+			}
+			count++
+
+			since := pr.CreatedAt
+			if c.measure == "stale" {
+				since = pr.UpdatedAt
+			}
+			if age := now.Sub(since); age > oldest {
+				oldest = age
+			}
+		}
+	}
+
+	if c.measure == "" {
+		return compare(count, c.op, c.count), strconv.Itoa(count)
+	}
+	if count == 0 {
+		// Without PRs there is no age to compare, and "stale<3d" would
+		// otherwise hold with an age of 0.
+		return false, "no PRs"
+	}
+	return compare(oldest, c.op, c.duration), formatAgeDays(oldest.Hours() / 24)
+}
+
+// checkGate returns a *gateError when any of conditions holds.
+func checkGate(conditions []gateCondition, repositories []RepositoryItem, now time.Time) error {
+	matched := []string{}
+	for _, c := range conditions {
+		if ok, value := c.evaluate(repositories, now); ok {
+			matched = append(matched, fmt.Sprintf("%s (%s)", c.expr, value))
+		}
+	}
+	if len(matched) > 0 {
+		return &gateError{matched: matched}
+	}
+	return nil
+}
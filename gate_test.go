@@ -0,0 +1,146 @@
+package main
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseAge(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"14d", 14 * 24 * time.Hour, false},
+		{"2w", 14 * 24 * time.Hour, false},
+		{"1.5d", 36 * time.Hour, false},
+		{"36h", 36 * time.Hour, false},
+		{"d", 0, true},
+		{"soon", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseAge(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseAge(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseAge(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseGateCondition(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    gateCondition
+		wantErr bool
+	}{
+		{"count>0", gateCondition{expr: "count>0", kind: "count", op: ">", count: 0}, false},
+		{"failing >= 3", gateCondition{expr: "failing >= 3", kind: "failing", op: ">=", count: 3}, false},
+		{"stale>14d", gateCondition{expr: "stale>14d", kind: "count", measure: "stale", op: ">", duration: 14 * 24 * time.Hour}, false},
+		{"failing.age>7d", gateCondition{expr: "failing.age>7d", kind: "failing", measure: "age", op: ">", duration: 7 * 24 * time.Hour}, false},
+		{"drafts=0", gateCondition{expr: "drafts=0", kind: "drafts", op: "=", count: 0}, false},
+		{"unknown>0", gateCondition{}, true},
+		{"count>many", gateCondition{}, true},
+		{"stale>soon", gateCondition{}, true},
+		{"count", gateCondition{}, true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseGateCondition(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseGateCondition(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseGateCondition(%q) = %+v, want %+v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestCheckGate(t *testing.T) {
+	now := time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)
+	repositories := []RepositoryItem{
+		{Name: "org/a", PullRequestItems: []PullRequestItem{
+			{Number: 1, CreatedAt: now.AddDate(0, 0, -10), UpdatedAt: now.AddDate(0, 0, -1), CheckStatus: checkStatusFailure},
+			{Number: 2, CreatedAt: now.AddDate(0, 0, -30), UpdatedAt: now.AddDate(0, 0, -20), CheckStatus: checkStatusSuccess},
+		}},
+		{Name: "org/b", PullRequestItems: []PullRequestItem{
+			{Number: 1, CreatedAt: now.AddDate(0, 0, -3), UpdatedAt: now.AddDate(0, 0, -3), CheckStatus: checkStatusFailure, IsDraft: true},
+		}},
+	}
+
+	tests := []struct {
+		conditions []string
+		wantErr    bool
+	}{
+		{[]string{}, false},
+		{[]string{"count>0"}, true},
+		{[]string{"count>3"}, false},
+		{[]string{"failing>1"}, true},
+		{[]string{"failing>3"}, false},
+		{[]string{"stale>14d"}, true},
+		{[]string{"stale>21d"}, false},
+		{[]string{"failing.age>7d"}, true},
+		{[]string{"failing.stale>7d"}, false},
+		{[]string{"drafts.age>7d"}, false},
+		{[]string{"approved>0", "pending>0"}, false},
+		{[]string{"approved>0", "count==3"}, true},
+		{[]string{"approved.stale<3d"}, false},
+	}
+
+	for _, tt := range tests {
+		conditions, err := parseGateConditions(tt.conditions)
+		if err != nil {
+			t.Fatalf("parseGateConditions(%q) error = %v", tt.conditions, err)
+		}
+		err = checkGate(conditions, repositories, now)
+		var gateErr *gateError
+		if got := errors.As(err, &gateErr); got != tt.wantErr {
+			t.Errorf("checkGate(%q) = %v, wantErr %v", tt.conditions, err, tt.wantErr)
+		}
+	}
+}
+
+func TestCheckGateEmpty(t *testing.T) {
+	now := time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)
+	tests := map[string]bool{
+		"count>0":   false,
+		"count=0":   true,
+		"stale<3d":  false,
+		"stale>14d": false,
+		"age>=0s":   false,
+		"age<=0s":   false,
+	}
+
+	for expr, wantErr := range tests {
+		conditions, err := parseGateConditions([]string{expr})
+		if err != nil {
+			t.Fatalf("parseGateConditions(%q) error = %v", expr, err)
+		}
+		err = checkGate(conditions, nil, now)
+		if (err != nil) != wantErr {
+			t.Errorf("checkGate(%q) on no PRs = %v, wantErr %v", expr, err, wantErr)
+		}
+	}
+}
+
+func TestCheckGateMessage(t *testing.T) {
+	now := time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)
+	repositories := []RepositoryItem{
+		{Name: "org/a", PullRequestItems: []PullRequestItem{{Number: 1, CreatedAt: now.AddDate(0, 0, -10)}}},
+	}
+	conditions, err := parseGateConditions([]string{"count>0", "age>7d", "drafts>0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = checkGate(conditions, repositories, now)
+	want := "fail-if matched: count>0 (1), age>7d (10d)"
+	if err == nil || err.Error() != want {
+		t.Errorf("checkGate() = %v, want %q", err, want)
+	}
+}
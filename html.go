@@ -0,0 +1,180 @@
+package main
+
+import (
+	"html/template"
+	"io"
+	"time"
+)
+
+type htmlPullRequest struct {
+	PullRequestItem
+	AgeDays int
+}
+
+// AgeClass buckets the time since the last update for coloring.
+func (hpr htmlPullRequest) AgeClass() string {
+	switch {
+	case hpr.AgeDays < 7:
+		return "fresh"
+	case hpr.AgeDays < 30:
+		return "aging"
+	default:
+		return "stale"
+	}
+}
+
+type htmlRepository struct {
+	Name         string
+	Url          string
+	PullRequests []htmlPullRequest
+}
+
+type htmlReport struct {
+	GeneratedAt time.Time
+	// Refresh is the number of seconds after which the page reloads
+	// itself, or 0 for a static page.
+	Refresh      int
+	Total        int
+	Drafts       int
+	Failing      int
+	Pending      int
+	Approved     int
+	Repositories []htmlRepository
+}
+
+func newHTMLReport(repositories []RepositoryItem, now time.Time) htmlReport {
+	report := htmlReport{GeneratedAt: now}
+	for _, repo := range repositories {
+		hr := htmlRepository{Name: repo.Name, Url: repo.Url}
+		for _, pr := range repo.PullRequestItems {
+			report.Total++
+			if pr.IsDraft {
+				report.Drafts++
+			}
+			switch pr.CheckStatus {
+			case checkStatusFailure:
+				report.Failing++
+			case checkStatusPending:
+				report.Pending++
+			}
+			if pr.ReviewDecision == reviewDecisionApproved {
+				report.Approved++
+			}
+			hr.PullRequests = append(hr.PullRequests, htmlPullRequest{
+				PullRequestItem: pr,
+				AgeDays:         int(now.Sub(pr.UpdatedAt).Hours() / 24),
+			})
+		}
+		report.Repositories = append(report.Repositories, hr)
+	}
+	return report
+}
+
+// writeHTML writes a self-contained HTML page with a sortable table per
+// repository and summary counts.
+func writeHTML(w io.Writer, repositories []RepositoryItem, now time.Time) error {
+	return writeHTMLReport(w, newHTMLReport(repositories, now))
+}
+
+func writeHTMLReport(w io.Writer, report htmlReport) error {
+	return htmlTemplate.Execute(w, report)
+}
+
+var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"date": func(t time.Time) string { return t.In(time.Local).Format("2006-01-02") },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+{{if .Refresh}}<meta http-equiv="refresh" content="{{.Refresh}}">
+{{end}}<title>Pull requests</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em; color: #1f2328; }
+a { color: #0969da; text-decoration: none; }
+a:hover { text-decoration: underline; }
+.summary { display: flex; gap: 1em; margin-bottom: 1.5em; }
+.summary div { border: 1px solid #d0d7de; border-radius: 6px; padding: 0.5em 1em; }
+.summary strong { display: block; font-size: 1.5em; }
+#filter { padding: 0.4em; width: 24em; margin-bottom: 1em; }
+table { border-collapse: collapse; width: 100%; margin-bottom: 2em; }
+th, td { text-align: left; padding: 0.3em 0.6em; border-bottom: 1px solid #d0d7de; }
+th { cursor: pointer; user-select: none; background: #f6f8fa; }
+.draft td { color: #656d76; }
+.badge { border-radius: 1em; padding: 0.1em 0.6em; font-size: 0.85em; color: #fff; }
+.SUCCESS { background: #1a7f37; }
+.FAILURE { background: #cf222e; }
+.PENDING { background: #9a6700; }
+.APPROVED { background: #8250df; }
+.CHANGES_REQUESTED { background: #cf222e; }
+.REVIEW_REQUIRED { background: #656d76; }
+.fresh { color: #1a7f37; }
+.aging { color: #9a6700; }
+.stale { color: #cf222e; font-weight: bold; }
+</style>
+</head>
+<body>
+<h1>Pull requests</h1>
+<p>Generated at {{.GeneratedAt.Format "2006-01-02 15:04 MST"}}</p>
+<div class="summary">
+<div><strong>{{.Total}}</strong>pull requests</div>
+<div><strong>{{len .Repositories}}</strong>repositories</div>
+<div><strong>{{.Drafts}}</strong>drafts</div>
+<div><strong>{{.Failing}}</strong>failing</div>
+<div><strong>{{.Pending}}</strong>pending</div>
+<div><strong>{{.Approved}}</strong>approved</div>
+</div>
+<input id="filter" type="search" placeholder="Filter by repository, author or title">
+{{range .Repositories}}
+<section>
+<h2><a href="{{.Url}}">{{.Name}}</a> <small>({{len .PullRequests}})</small></h2>
+<table>
+<thead><tr><th>#</th><th>Title</th><th>Author</th><th>Updated</th><th>Age</th><th>Checks</th><th>Review</th></tr></thead>
+<tbody>
+{{range .PullRequests}}<tr{{if .IsDraft}} class="draft"{{end}}>
+<td data-sort="{{.Number}}"><a href="{{.Url}}">#{{.Number}}</a></td>
+<td>{{.Title}}{{if .IsDraft}} (draft){{end}}</td>
+<td>{{.Author}}</td>
+<td data-sort="{{.UpdatedAt.Unix}}">{{date .UpdatedAt}}</td>
+<td data-sort="{{.AgeDays}}" class="{{.AgeClass}}">{{.AgeDays}}d</td>
+<td>{{if ne .CheckStatus "UNKNOWN"}}<span class="badge {{.CheckStatus}}">{{.CheckStatus}}</span>{{end}}</td>
+<td>{{if .ReviewDecision}}<span class="badge {{.ReviewDecision}}">{{.ReviewDecision}}</span>{{end}}</td>
+</tr>
+{{end}}</tbody>
+</table>
+</section>
+{{end}}
+<script>
+document.querySelectorAll("th").forEach(function (th) {
+  th.addEventListener("click", function () {
+    var table = th.closest("table");
+    var tbody = table.querySelector("tbody");
+    var index = Array.prototype.indexOf.call(th.parentNode.children, th);
+    var asc = th.dataset.order !== "asc";
+    th.dataset.order = asc ? "asc" : "desc";
+    var value = function (row) {
+      var cell = row.children[index];
+      return cell.dataset.sort !== undefined ? Number(cell.dataset.sort) : cell.textContent.trim().toLowerCase();
+    };
+    Array.from(tbody.rows).sort(function (a, b) {
+      var x = value(a), y = value(b);
+      return (x < y ? -1 : x > y ? 1 : 0) * (asc ? 1 : -1);
+    }).forEach(function (row) { tbody.appendChild(row); });
+  });
+});
+document.getElementById("filter").addEventListener("input", function (e) {
+  var query = e.target.value.toLowerCase();
+  document.querySelectorAll("section").forEach(function (section) {
+    var repo = section.querySelector("h2").textContent.toLowerCase();
+    var visible = 0;
+    section.querySelectorAll("tbody tr").forEach(function (row) {
+      var match = repo.indexOf(query) >= 0 || row.textContent.toLowerCase().indexOf(query) >= 0;
+      row.style.display = match ? "" : "none";
+      if (match) visible++;
+    });
+    section.style.display = visible > 0 ? "" : "none";
+  });
+});
+</script>
+</body>
+</html>
+`))
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewHTMLReport(t *testing.T) {
+	now := time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)
+	repositories := []RepositoryItem{
+		{
+			Name: "test/repo",
+			Url:  "https://github.com/test/repo",
+			PullRequestItems: []PullRequestItem{
+				{Number: 3, UpdatedAt: now.AddDate(0, 0, -1), CheckStatus: checkStatusFailure, ReviewDecision: reviewDecisionApproved},
+				{Number: 2, UpdatedAt: now.AddDate(0, 0, -10), CheckStatus: checkStatusPending, IsDraft: true},
+				{Number: 1, UpdatedAt: now.AddDate(0, 0, -45), CheckStatus: checkStatusSuccess},
+			},
+		},
+	}
+
+	report := newHTMLReport(repositories, now)
+
+	if report.Total != 3 || report.Drafts != 1 || report.Failing != 1 || report.Pending != 1 || report.Approved != 1 {
+		t.Errorf("counts = %+v, want total 3, drafts 1, failing 1, pending 1, approved 1", report)
+	}
+	if report.Repositories[0].Url != "https://github.com/test/repo" {
+		t.Errorf("Url = %q, want %q", report.Repositories[0].Url, "https://github.com/test/repo")
+	}
+
+	wantClasses := []string{"fresh", "aging", "stale"}
+	for i, want := range wantClasses {
+		if got := report.Repositories[0].PullRequests[i].AgeClass(); got != want {
+			t.Errorf("PullRequests[%d].AgeClass() = %q, want %q", i, got, want)
+		}
+	}
+}
+
+func TestWriteHTML(t *testing.T) {
+	now := time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)
+	repositories := []RepositoryItem{
+		{
+			Name: "test/repo",
+			Url:  "https://github.com/test/repo",
+			PullRequestItems: []PullRequestItem{
+				{Number: 1, Title: "<script>alert(1)</script>", Url: "https://github.com/test/repo/pull/1", UpdatedAt: now, CheckStatus: checkStatusSuccess},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := writeHTML(&buf, repositories, now); err != nil {
+		t.Fatalf("writeHTML() returned error: %v", err)
+	}
+	result := buf.String()
+
+	wantContains := []string{
+		`<a href="https://github.com/test/repo">test/repo</a>`,
+		`<a href="https://github.com/test/repo/pull/1">#1</a>`,
+		`&lt;script&gt;alert(1)&lt;/script&gt;`,
+		`<span class="badge SUCCESS">SUCCESS</span>`,
+	}
+	for _, want := range wantContains {
+		if !strings.Contains(result, want) {
+			t.Errorf("writeHTML() want to contain %q", want)
+		}
+	}
+	if strings.Contains(result, "http-equiv") {
+		t.Errorf("writeHTML() want no auto-refresh in a static report")
+	}
+}
@@ -2,10 +2,17 @@ package main
 
 import (
 	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 	"github.com/pkg/browser"
 )
 
@@ -22,12 +29,158 @@ func (li listItem) FilterValue() string {
 	return li.pullRequestItem.RepositoryName + li.pullRequestItem.Author + li.pullRequestItem.Title
 }
 
+// groupHeaderItem is a section header preceding the items of a group.
+type groupHeaderItem struct {
+	name      string
+	count     int
+	collapsed bool
+}
+
+func (gi groupHeaderItem) Title() string {
+	if gi.collapsed {
+		return "▸ " + gi.name
+	}
+	return "▾ " + gi.name
+}
+func (gi groupHeaderItem) Description() string {
+	if gi.count == 1 {
+		return "1 PR"
+	}
+	return fmt.Sprintf("%d PRs", gi.count)
+}
+func (gi groupHeaderItem) FilterValue() string { return "" }
+
+type grouping int
+
+const (
+	groupByRepository grouping = iota
+	groupByAuthor
+	groupByOrg
+	groupNone
+)
+
+func (g grouping) key(pri *PullRequestItem) string {
+	switch g {
+	case groupByAuthor:
+		return pri.Author
+	case groupByOrg:
+		org, _, _ := strings.Cut(pri.RepositoryName, "/")
+		return org
+	default:
+		return pri.RepositoryName
+	}
+}
+
+type selectedListItem struct {
+	listItem
+}
+
+func (si selectedListItem) Title() string { return "● " + si.listItem.Title() }
+
+// itemDelegate renders items like the default delegate, marking the ones
+// in selection.
+type itemDelegate struct {
+	list.DefaultDelegate
+	selection map[string]bool
+}
+
+func (d itemDelegate) Render(w io.Writer, m list.Model, index int, item list.Item) {
+	if li, ok := item.(listItem); ok && d.selection[li.pullRequestItem.Url] {
+		item = selectedListItem{li}
+	}
+	d.DefaultDelegate.Render(w, m, index, item)
+}
+
+type promptKind int
+
+const (
+	promptNone promptKind = iota
+	promptLabel
+	promptReviewer
+	promptClose
+	promptQuery
+)
+
+// batchResultMsg reports the outcome of a batch action.
+type batchResultMsg struct {
+	action string
+	done   int
+	errs   []error
+}
+
+func (msg batchResultMsg) String() string {
+	s := fmt.Sprintf("%s %d PRs", msg.action, msg.done)
+	if len(msg.errs) > 0 {
+		s += fmt.Sprintf(", %d failed: %s", len(msg.errs), msg.errs[0])
+	}
+	return s
+}
+
+func runBatch(action string, items []PullRequestItem, fn func(pri *PullRequestItem) error) tea.Cmd {
+	return func() tea.Msg {
+		result := batchResultMsg{action: action}
+		for i := range items {
+			if err := fn(&items[i]); err != nil {
+				result.errs = append(result.errs, fmt.Errorf("%s#%d: %w", items[i].RepositoryName, items[i].Number, err))
+				continue
+			}
+			result.done++
+		}
+		return result
+	}
+}
+
+// viewerMsg reports the login of the current user on host, or why it could
+// not be looked up.
+type viewerMsg struct {
+	host  string
+	login string
+	err   error
+}
+
+func fetchViewer(host string) tea.Cmd {
+	return func() tea.Msg {
+		login, err := fetchViewerLogin(host)
+		return viewerMsg{host: host, login: login, err: err}
+	}
+}
+
 type model struct {
-	list list.Model
-	keys *listKeyMap
+	list      list.Model
+	keys      *listKeyMap
+	items     []listItem
+	title     string
+	filter    localFilter
+	viewers   map[string]string
+	grouping  grouping
+	collapsed map[string]bool
+	selection map[string]bool
+	prompt    promptKind
+	input     textinput.Model
+	width     int
+	height    int
 }
 
-func (m model) Init() tea.Cmd { return nil }
+func (m model) Init() tea.Cmd {
+	cmds := []tea.Cmd{}
+	for _, host := range m.hosts() {
+		cmds = append(cmds, fetchViewer(host))
+	}
+	return tea.Batch(cmds...)
+}
+
+// hosts returns the hosts of the pull requests, where the current user is
+// looked up for "is:mine".
+func (m *model) hosts() []string {
+	hosts := []string{}
+	for _, item := range m.items {
+		if host := pullRequestHost(&item.pullRequestItem); !slices.Contains(hosts, host) {
+			hosts = append(hosts, host)
+		}
+	}
+	sort.Strings(hosts)
+	return hosts
+}
 
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
@@ -36,14 +189,102 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, tea.Quit
 		}
 
-		if key.Matches(msg, m.keys.openWithBrowser) {
+		if m.prompt != promptNone {
+			return m.updatePrompt(msg)
+		}
+
+		if m.list.SettingFilter() {
+			break
+		}
+
+		switch {
+		case key.Matches(msg, m.keys.openWithBrowser):
+			for _, pri := range m.targets() {
+				_ = browser.OpenURL(pri.Url)
+			}
+			return m, nil
+		case key.Matches(msg, m.keys.toggleSelection):
 			if item, ok := m.list.SelectedItem().(listItem); ok {
-				_ = browser.OpenURL(item.pullRequestItem.Url)
+				url := item.pullRequestItem.Url
+				if m.selection[url] {
+					delete(m.selection, url)
+				} else {
+					m.selection[url] = true
+				}
+				m.list.CursorDown()
 			}
 			return m, nil
+		case key.Matches(msg, m.keys.selectAll):
+			m.toggleSelectAll()
+			return m, nil
+		case key.Matches(msg, m.keys.copyURL):
+			cmd := m.copyTargets("URLs", func(pri *PullRequestItem) string {
+				return pri.Url
+			})
+			return m, cmd
+		case key.Matches(msg, m.keys.copyReference):
+			cmd := m.copyTargets("references", pullRequestReference)
+			return m, cmd
+		case key.Matches(msg, m.keys.copyMarkdown):
+			cmd := m.copyTargets("markdown links", func(pri *PullRequestItem) string {
+				return markdownLink(pri.Title, pri.Url)
+			})
+			return m, cmd
+		case key.Matches(msg, m.keys.addLabel):
+			cmd := m.startPrompt(promptLabel, "label: ")
+			return m, cmd
+		case key.Matches(msg, m.keys.requestReviewer):
+			cmd := m.startPrompt(promptReviewer, "reviewer: ")
+			return m, cmd
+		case key.Matches(msg, m.keys.closePullRequest):
+			cmd := m.startPrompt(promptClose, fmt.Sprintf("close %d PRs? [y/N] ", len(m.targets())))
+			return m, cmd
+		case key.Matches(msg, m.keys.toggleDrafts):
+			m.filter.cycleDraft()
+			cmd := m.refreshItems()
+			return m, cmd
+		case key.Matches(msg, m.keys.cycleCheckStatus):
+			m.filter.cycleCheckStatus()
+			cmd := m.refreshItems()
+			return m, cmd
+		case key.Matches(msg, m.keys.cycleReview):
+			m.filter.cycleReview()
+			cmd := m.refreshItems()
+			return m, cmd
+		case key.Matches(msg, m.keys.toggleMine):
+			m.filter.mine = !m.filter.mine
+			cmd := m.refreshItems()
+			return m, cmd
+		case key.Matches(msg, m.keys.cycleGrouping):
+			m.grouping = (m.grouping + 1) % (groupNone + 1)
+			m.collapsed = map[string]bool{}
+			cmd := m.refreshItems()
+			return m, cmd
+		case key.Matches(msg, m.keys.toggleGroup):
+			if header, ok := m.list.SelectedItem().(groupHeaderItem); ok {
+				m.collapsed[header.name] = !header.collapsed
+				cmd := m.refreshItems()
+				return m, cmd
+			}
+		case key.Matches(msg, m.keys.query):
+			cmd := m.startPrompt(promptQuery, "filter: ")
+			m.input.SetValue(m.filter.String())
+			return m, cmd
 		}
 	case tea.WindowSizeMsg:
-		m.list.SetSize(msg.Width, msg.Height)
+		m.width, m.height = msg.Width, msg.Height
+		m.resize()
+	case batchResultMsg:
+		cmd := m.list.NewStatusMessage(msg.String())
+		return m, cmd
+	case viewerMsg:
+		if msg.err != nil {
+			cmd := m.list.NewStatusMessage(fmt.Sprintf("is:mine unavailable on %s: %s", msg.host, msg.err))
+			return m, cmd
+		}
+		m.viewers[msg.host] = msg.login
+		cmd := m.refreshItems()
+		return m, cmd
 	}
 
 	var cmd tea.Cmd
@@ -51,49 +292,371 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
-func (m model) View() string { return m.list.View() }
+func (m model) View() string {
+	if m.prompt == promptNone {
+		return m.list.View()
+	}
+	return m.list.View() + "\n" + m.input.View()
+}
+
+// refreshItems rebuilds the list from the items passing the local filter,
+// under a header per group unless grouping is off.
+func (m *model) refreshItems() tea.Cmd {
+	groups := map[string][]listItem{}
+	names := []string{}
+	for _, item := range m.items {
+		if !m.filter.matches(&item.pullRequestItem, m.viewers[pullRequestHost(&item.pullRequestItem)]) {
+			continue
+		}
+		name := m.grouping.key(&item.pullRequestItem)
+		if _, ok := groups[name]; !ok {
+			names = append(names, name)
+		}
+		groups[name] = append(groups[name], item)
+	}
+	sort.Strings(names)
+
+	items := []list.Item{}
+	for _, name := range names {
+		if m.grouping != groupNone {
+			items = append(items, groupHeaderItem{name: name, count: len(groups[name]), collapsed: m.collapsed[name]})
+			if m.collapsed[name] {
+				continue
+			}
+		}
+		for _, item := range groups[name] {
+			items = append(items, item)
+		}
+	}
+
+	m.list.Title = m.title
+	if query := m.filter.String(); query != "" {
+		m.list.Title += fmt.Sprintf(" [%s]", query)
+	}
+	return m.list.SetItems(items)
+}
+
+// resize fits the list into the window, leaving a line for the prompt
+// while one is shown.
+func (m *model) resize() {
+	height := m.height
+	if m.prompt != promptNone {
+		height--
+	}
+	m.list.SetSize(m.width, height)
+}
+
+// targets returns the selected pull requests, including those in collapsed
+// groups or hidden by the filter, or the one under the cursor when nothing
+// is selected.
+func (m *model) targets() []PullRequestItem {
+	targets := []PullRequestItem{}
+	for _, item := range m.items {
+		if m.selection[item.pullRequestItem.Url] {
+			targets = append(targets, item.pullRequestItem)
+		}
+	}
+	if len(targets) > 0 {
+		return targets
+	}
+
+	if item, ok := m.list.SelectedItem().(listItem); ok {
+		targets = append(targets, item.pullRequestItem)
+	}
+	return targets
+}
+
+// copyTargets copies one line per target, rendered by format, to the
+// clipboard.
+func (m *model) copyTargets(what string, format func(pri *PullRequestItem) string) tea.Cmd {
+	targets := m.targets()
+	if len(targets) == 0 {
+		// Copying nothing would clear the clipboard.
+		return m.list.NewStatusMessage("no PRs to copy")
+	}
+	lines := make([]string, 0, len(targets))
+	for i := range targets {
+		lines = append(lines, format(&targets[i]))
+	}
+
+	status := fmt.Sprintf("copied %d %s", len(lines), what)
+	if err := copyToClipboard(strings.Join(lines, "\n")); err != nil {
+		status = fmt.Sprintf("copy failed: %s", err)
+	}
+	return m.list.NewStatusMessage(status)
+}
+
+// toggleSelectAll selects every visible item, or deselects them when they
+// are all selected already.
+func (m *model) toggleSelectAll() {
+	visible := []string{}
+	allSelected := true
+	for _, item := range m.list.VisibleItems() {
+		if li, ok := item.(listItem); ok {
+			visible = append(visible, li.pullRequestItem.Url)
+			allSelected = allSelected && m.selection[li.pullRequestItem.Url]
+		}
+	}
+
+	for _, url := range visible {
+		if allSelected {
+			delete(m.selection, url)
+		} else {
+			m.selection[url] = true
+		}
+	}
+}
+
+func (m *model) startPrompt(kind promptKind, prompt string) tea.Cmd {
+	m.prompt = kind
+	m.input.Prompt = prompt
+	m.input.SetValue("")
+	m.resize()
+	return m.input.Focus()
+}
+
+func (m *model) endPrompt() {
+	m.prompt = promptNone
+	m.input.Blur()
+	m.resize()
+}
+
+func (m model) updatePrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.endPrompt()
+		return m, nil
+	case tea.KeyEnter:
+		kind, value := m.prompt, strings.TrimSpace(m.input.Value())
+		m.endPrompt()
+		cmd := m.runPrompt(kind, value)
+		return m, cmd
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+func (m *model) runPrompt(kind promptKind, value string) tea.Cmd {
+	if kind == promptQuery {
+		filter, err := parseLocalFilter(value)
+		if err != nil {
+			return m.list.NewStatusMessage(err.Error())
+		}
+		m.filter = filter
+		return m.refreshItems()
+	}
+
+	targets := m.targets()
+	switch kind {
+	case promptLabel:
+		labels := splitValues(value)
+		if len(labels) == 0 {
+			return nil
+		}
+		return runBatch("labeled", targets, func(pri *PullRequestItem) error {
+			return addLabels(pri, labels)
+		})
+	case promptReviewer:
+		reviewers := splitValues(value)
+		if len(reviewers) == 0 {
+			return nil
+		}
+		return runBatch("requested review on", targets, func(pri *PullRequestItem) error {
+			return requestReviewers(pri, reviewers)
+		})
+	case promptClose:
+		if value != "y" && value != "yes" {
+			return nil
+		}
+		return runBatch("closed", targets, closePullRequest)
+	}
+	return nil
+}
 
 type listKeyMap struct {
-	openWithBrowser key.Binding
+	openWithBrowser  key.Binding
+	toggleSelection  key.Binding
+	selectAll        key.Binding
+	copyURL          key.Binding
+	copyReference    key.Binding
+	copyMarkdown     key.Binding
+	addLabel         key.Binding
+	requestReviewer  key.Binding
+	closePullRequest key.Binding
+	toggleDrafts     key.Binding
+	cycleCheckStatus key.Binding
+	cycleReview      key.Binding
+	toggleMine       key.Binding
+	query            key.Binding
+	cycleGrouping    key.Binding
+	toggleGroup      key.Binding
+}
+
+// defaultKeys are the key bindings by action name.
+var defaultKeys = map[string][]string{
+	"open":             {"o"},
+	"select":           {" "},
+	"select_all":       {"a"},
+	"copy_url":         {"y"},
+	"copy_reference":   {"Y"},
+	"copy_markdown":    {"M"},
+	"add_label":        {"L"},
+	"request_reviewer": {"R"},
+	"close":            {"X"},
+	"drafts":           {"D"},
+	"check_status":     {"S"},
+	"review":           {"V"},
+	"mine":             {"@"},
+	"query":            {":"},
+	"group_by":         {"tab"},
+	"collapse":         {"enter"},
 }
 
-func newListKeyMap() *listKeyMap {
+// validateKeys reports actions in overrides that do not exist, and keys
+// bound to two actions, or to an action and a key of the list itself, once
+// overrides are applied to the defaults.
+func validateKeys(overrides map[string][]string) error {
+	actions := make([]string, 0, len(defaultKeys))
+	for action := range defaultKeys {
+		actions = append(actions, action)
+	}
+	sort.Strings(actions)
+
+	for action := range overrides {
+		if _, ok := defaultKeys[action]; !ok {
+			return fmt.Errorf("unknown key action %q, actions are %s", action, strings.Join(actions, ", "))
+		}
+	}
+
+	bound := listKeys()
+	for _, action := range actions {
+		keys := defaultKeys[action]
+		if configured := overrides[action]; len(configured) > 0 {
+			keys = configured
+		}
+		for _, k := range keys {
+			if other, ok := bound[k]; ok {
+				return fmt.Errorf("key %q is bound to both %s and %s", k, other, action)
+			}
+			bound[k] = action
+		}
+	}
+	return nil
+}
+
+// listKeys maps the keys the list handles itself outside filtering to what
+// they do. The bindings of actions must leave them alone.
+func listKeys() map[string]string {
+	km := list.DefaultKeyMap()
+	bound := map[string]string{}
+	for _, b := range []key.Binding{km.CursorUp, km.CursorDown, km.PrevPage, km.NextPage, km.GoToStart, km.GoToEnd, km.Filter, km.ClearFilter, km.ShowFullHelp, km.Quit, km.ForceQuit} {
+		for _, k := range b.Keys() {
+			bound[k] = "list " + b.Help().Desc
+		}
+	}
+	return bound
+}
+
+// newBinding builds the binding for action, using the keys configured for
+// it in overrides when present.
+func newBinding(overrides map[string][]string, action string, help string) key.Binding {
+	keys := defaultKeys[action]
+	if configured := overrides[action]; len(configured) > 0 {
+		keys = configured
+	}
+
+	names := make([]string, 0, len(keys))
+	for _, k := range keys {
+		if k == " " {
+			k = "space"
+		}
+		names = append(names, k)
+	}
+	return key.NewBinding(
+		key.WithKeys(keys...),
+		key.WithHelp(strings.Join(names, "/"), help),
+	)
+}
+
+// newListKeyMap builds the key map, overriding the defaults with the keys
+// configured by action name, e.g. "open" or "select_all".
+func newListKeyMap(overrides map[string][]string) *listKeyMap {
 	return &listKeyMap{
-		openWithBrowser: key.NewBinding(
-			key.WithKeys("o"),
-			key.WithHelp("o", "open with browser"),
-		),
+		openWithBrowser:  newBinding(overrides, "open", "open with browser"),
+		toggleSelection:  newBinding(overrides, "select", "select"),
+		selectAll:        newBinding(overrides, "select_all", "select all"),
+		copyURL:          newBinding(overrides, "copy_url", "copy URL"),
+		copyReference:    newBinding(overrides, "copy_reference", "copy owner/repo#N"),
+		copyMarkdown:     newBinding(overrides, "copy_markdown", "copy markdown link"),
+		addLabel:         newBinding(overrides, "add_label", "add label"),
+		requestReviewer:  newBinding(overrides, "request_reviewer", "request reviewer"),
+		closePullRequest: newBinding(overrides, "close", "close"),
+		toggleDrafts:     newBinding(overrides, "drafts", "drafts"),
+		cycleCheckStatus: newBinding(overrides, "check_status", "check status"),
+		cycleReview:      newBinding(overrides, "review", "review decision"),
+		toggleMine:       newBinding(overrides, "mine", "mine"),
+		query:            newBinding(overrides, "query", "query"),
+		cycleGrouping:    newBinding(overrides, "group_by", "group by"),
+		toggleGroup:      newBinding(overrides, "collapse", "collapse/expand"),
 	}
 }
 
-func printResultInteractive(orgs []string, repositories []RepositoryItem, noColor bool) error {
-	formatter := NewFormatter(noColor)
-	items := []list.Item{}
+// newModel builds the interactive model listing the pull requests of
+// repositories.
+func newModel(orgs []string, repositories []RepositoryItem, opts *Options) model {
+	formatter := NewFormatter(opts.NoColor, &opts.Config.Theme)
+	items := []listItem{}
 	for _, repo := range repositories {
 		for _, pr := range repo.PullRequestItems {
 			items = append(items, listItem{pullRequestItem: pr, formatter: formatter})
 		}
 	}
 
-	listKeys := newListKeyMap()
-	prList := list.New(items, list.NewDefaultDelegate(), 0, 0)
+	listKeys := newListKeyMap(opts.Config.Keys)
+	selection := map[string]bool{}
+	delegate := itemDelegate{DefaultDelegate: list.NewDefaultDelegate(), selection: selection}
+	if !opts.NoColor {
+		accent := lipgloss.Color(strconv.Itoa(int(opts.Config.Theme.Accent)))
+		delegate.Styles.SelectedTitle = delegate.Styles.SelectedTitle.Foreground(accent).BorderForeground(accent)
+		delegate.Styles.SelectedDesc = delegate.Styles.SelectedDesc.Foreground(accent).BorderForeground(accent)
+	}
+	prList := list.New([]list.Item{}, delegate, 0, 0)
 	prList.AdditionalFullHelpKeys = func() []key.Binding {
 		return []key.Binding{
 			listKeys.openWithBrowser,
+			listKeys.toggleSelection,
+			listKeys.selectAll,
+			listKeys.copyURL,
+			listKeys.copyReference,
+			listKeys.copyMarkdown,
+			listKeys.addLabel,
+			listKeys.requestReviewer,
+			listKeys.closePullRequest,
+			listKeys.toggleDrafts,
+			listKeys.cycleCheckStatus,
+			listKeys.cycleReview,
+			listKeys.toggleMine,
+			listKeys.query,
+			listKeys.cycleGrouping,
+			listKeys.toggleGroup,
 		}
 	}
 	prList.AdditionalShortHelpKeys = func() []key.Binding {
 		return []key.Binding{
 			listKeys.openWithBrowser,
+			listKeys.toggleSelection,
 		}
 	}
-	m := model{list: prList, keys: listKeys}
-	if len(orgs) == 1 {
-		m.list.Title = fmt.Sprintf("PRs in %s", orgs[0])
-	} else {
-		m.list.Title = fmt.Sprintf("PRs in %d orgs", len(orgs))
-	}
-	p := tea.NewProgram(m, tea.WithAltScreen())
+	m := model{list: prList, keys: listKeys, items: items, viewers: map[string]string{}, collapsed: map[string]bool{}, selection: selection, input: textinput.New()}
+	m.title = resultTitle(orgs)
+	m.refreshItems()
+	return m
+}
+
+func printResultInteractive(orgs []string, repositories []RepositoryItem, opts *Options) error {
+	p := tea.NewProgram(newModel(orgs, repositories, opts), tea.WithAltScreen())
 
 	if _, err := p.Run(); err != nil {
 		return err
@@ -0,0 +1,275 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestNewListKeyMap(t *testing.T) {
+	keys := newListKeyMap(map[string][]string{"open": {"ctrl+o", "O"}})
+
+	if got := keys.openWithBrowser.Keys(); !reflect.DeepEqual(got, []string{"ctrl+o", "O"}) {
+		t.Errorf("open keys = %v, want [ctrl+o O]", got)
+	}
+	if got := keys.openWithBrowser.Help().Key; got != "ctrl+o/O" {
+		t.Errorf("open help = %q, want %q", got, "ctrl+o/O")
+	}
+	if got := keys.toggleSelection.Help().Key; got != "space" {
+		t.Errorf("select help = %q, want %q", got, "space")
+	}
+}
+
+func TestValidateKeys(t *testing.T) {
+	tests := []struct {
+		name      string
+		overrides map[string][]string
+		wantErr   string
+	}{
+		{"defaults", nil, ""},
+		{"override", map[string][]string{"open": {"O"}, "copy_url": {"U"}}, ""},
+		{"swap", map[string][]string{"open": {"enter"}, "collapse": {"o"}}, ""},
+		{"unknown action", map[string][]string{"copy_urls": {"U"}}, `unknown key action "copy_urls"`},
+		{"conflict with a default", map[string][]string{"open": {"enter"}}, `key "enter" is bound to both collapse and open`},
+		{"conflict between overrides", map[string][]string{"open": {"U"}, "copy_url": {"U"}}, `key "U" is bound to both copy_url and open`},
+		{"conflict with the list", map[string][]string{"open": {"q"}}, `key "q" is bound to both list quit and open`},
+		{"conflict with list navigation", map[string][]string{"copy_url": {"j"}}, `key "j" is bound to both list down and copy_url`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateKeys(tt.overrides)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("validateKeys() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("validateKeys() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func testPullRequest(repo string, number int) PullRequestItem {
+	return PullRequestItem{
+		Number:         number,
+		Title:          fmt.Sprintf("PR %d", number),
+		Author:         "alice",
+		RepositoryName: repo,
+		Url:            fmt.Sprintf("https://github.com/%s/pull/%d", repo, number),
+	}
+}
+
+// newTestModel returns the model of repositories org/a with PRs 1 and 2,
+// and org/b with PR 3, sized to show them all.
+func newTestModel() model {
+	repositories := []RepositoryItem{
+		{Name: "org/a", PullRequestItems: []PullRequestItem{testPullRequest("org/a", 1), testPullRequest("org/a", 2)}},
+		{Name: "org/b", PullRequestItems: []PullRequestItem{testPullRequest("org/b", 3)}},
+	}
+	m := newModel([]string{"org"}, repositories, &Options{NoColor: true, Config: defaultConfig()})
+	return updateModel(m, tea.WindowSizeMsg{Width: 80, Height: 40})
+}
+
+func updateModel(m model, msgs ...tea.Msg) model {
+	for _, msg := range msgs {
+		next, _ := m.Update(msg)
+		m = next.(model)
+	}
+	return m
+}
+
+func keyPress(s string) tea.KeyMsg {
+	switch s {
+	case " ":
+		return tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}}
+	case "down":
+		return tea.KeyMsg{Type: tea.KeyDown}
+	case "up":
+		return tea.KeyMsg{Type: tea.KeyUp}
+	case "tab":
+		return tea.KeyMsg{Type: tea.KeyTab}
+	case "enter":
+		return tea.KeyMsg{Type: tea.KeyEnter}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+func targetNumbers(m model) []int {
+	numbers := []int{}
+	for _, pri := range m.targets() {
+		numbers = append(numbers, pri.Number)
+	}
+	return numbers
+}
+
+func TestModelSelection(t *testing.T) {
+	m := newTestModel()
+
+	if got := targetNumbers(m); len(got) != 0 {
+		t.Errorf("targets() on a group header = %v, want none", got)
+	}
+
+	m = updateModel(m, keyPress("down"))
+	if got := targetNumbers(m); !reflect.DeepEqual(got, []int{1}) {
+		t.Errorf("targets() without selection = %v, want the PR under the cursor [1]", got)
+	}
+
+	m = updateModel(m, keyPress(" "))
+	if !m.selection["https://github.com/org/a/pull/1"] || len(m.selection) != 1 {
+		t.Errorf("selection after space = %v, want PR 1", m.selection)
+	}
+	if got := targetNumbers(m); !reflect.DeepEqual(got, []int{1}) {
+		t.Errorf("targets() = %v, want the selected PRs [1], not the cursor", got)
+	}
+
+	m = updateModel(m, keyPress(" "))
+	if got := targetNumbers(m); !reflect.DeepEqual(got, []int{1, 2}) {
+		t.Errorf("targets() after selecting the next PR = %v, want [1 2]", got)
+	}
+
+	m = updateModel(m, keyPress("a"))
+	if got := targetNumbers(m); !reflect.DeepEqual(got, []int{1, 2, 3}) {
+		t.Errorf("targets() after select all = %v, want [1 2 3]", got)
+	}
+
+	m = updateModel(m, keyPress("a"))
+	if len(m.selection) != 0 {
+		t.Errorf("selection after the second select all = %v, want empty", m.selection)
+	}
+	m = updateModel(m, keyPress("down"))
+	if got := targetNumbers(m); !reflect.DeepEqual(got, []int{3}) {
+		t.Errorf("targets() after deselecting all = %v, want the PR under the cursor [3]", got)
+	}
+}
+
+func TestModelSpaceOnGroupHeader(t *testing.T) {
+	m := updateModel(newTestModel(), keyPress(" "))
+	if len(m.selection) != 0 {
+		t.Errorf("selection after space on a group header = %v, want empty", m.selection)
+	}
+}
+
+func TestModelViewerError(t *testing.T) {
+	m := updateModel(newTestModel(), viewerMsg{host: "github.com", err: errors.New("HTTP 401")})
+	if len(m.viewers) != 0 {
+		t.Errorf("viewers = %v, want none", m.viewers)
+	}
+	if view := m.View(); !strings.Contains(view, "HTTP 401") {
+		t.Errorf("View() does not show the error:\n%s", view)
+	}
+
+	m = updateModel(m, viewerMsg{host: "github.com", login: "alice"})
+	if m.viewers["github.com"] != "alice" {
+		t.Errorf("viewers = %v, want alice on github.com", m.viewers)
+	}
+}
+
+func TestModelMineOnMixedHosts(t *testing.T) {
+	ghe := testPullRequest("platform/api", 4)
+	ghe.Url = "https://ghe.corp.example/platform/api/pull/4"
+	repositories := []RepositoryItem{
+		{Name: "org/a", PullRequestItems: []PullRequestItem{testPullRequest("org/a", 1)}},
+		{Name: "platform/api", PullRequestItems: []PullRequestItem{ghe}},
+	}
+	m := newModel([]string{"org", "ghe.corp.example/platform"}, repositories, &Options{NoColor: true, Config: defaultConfig()})
+	m = updateModel(m, tea.WindowSizeMsg{Width: 80, Height: 40})
+
+	if got, want := m.hosts(), []string{"ghe.corp.example", "github.com"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("hosts() = %q, want %q", got, want)
+	}
+
+	m = updateModel(m, viewerMsg{host: "github.com", login: "alice"}, viewerMsg{host: "ghe.corp.example", login: "a.smith"}, keyPress("@"))
+	want := []string{"▾ org/a", "#1"}
+	if got := listTitles(m); !reflect.DeepEqual(got, want) {
+		t.Errorf("items with is:mine = %q, want %q", got, want)
+	}
+}
+
+// listTitles returns the titles of the list items, "▾ org/a" for headers
+// and "#1" for pull requests.
+func listTitles(m model) []string {
+	titles := []string{}
+	for _, item := range m.list.Items() {
+		switch item := item.(type) {
+		case groupHeaderItem:
+			titles = append(titles, item.Title())
+		case listItem:
+			titles = append(titles, fmt.Sprintf("#%d", item.pullRequestItem.Number))
+		}
+	}
+	return titles
+}
+
+func TestModelGrouping(t *testing.T) {
+	m := newTestModel()
+	want := []string{"▾ org/a", "#1", "#2", "▾ org/b", "#3"}
+	if got := listTitles(m); !reflect.DeepEqual(got, want) {
+		t.Errorf("items = %q, want %q", got, want)
+	}
+
+	m = updateModel(m, keyPress("tab"))
+	want = []string{"▾ alice", "#1", "#2", "#3"}
+	if got := listTitles(m); !reflect.DeepEqual(got, want) {
+		t.Errorf("items grouped by author = %q, want %q", got, want)
+	}
+
+	m = updateModel(m, keyPress("tab"), keyPress("tab"))
+	want = []string{"#1", "#2", "#3"}
+	if got := listTitles(m); !reflect.DeepEqual(got, want) {
+		t.Errorf("items without grouping = %q, want %q", got, want)
+	}
+}
+
+func TestModelCollapseKeepsSelection(t *testing.T) {
+	m := updateModel(newTestModel(), keyPress("down"), keyPress(" "), keyPress(" "), keyPress("up"), keyPress("up"), keyPress("up"))
+	if header, ok := m.list.SelectedItem().(groupHeaderItem); !ok || header.name != "org/a" {
+		t.Fatalf("cursor on %v, want the org/a header", m.list.SelectedItem())
+	}
+
+	m = updateModel(m, keyPress("enter"))
+	want := []string{"▸ org/a", "▾ org/b", "#3"}
+	if got := listTitles(m); !reflect.DeepEqual(got, want) {
+		t.Errorf("items after collapse = %q, want %q", got, want)
+	}
+	if got := targetNumbers(m); !reflect.DeepEqual(got, []int{1, 2}) {
+		t.Errorf("targets() in a collapsed group = %v, want [1 2]", got)
+	}
+
+	m = updateModel(m, keyPress("X"))
+	if got, want := m.input.Prompt, "close 2 PRs? [y/N] "; got != want {
+		t.Errorf("close prompt = %q, want %q", got, want)
+	}
+
+	m = updateModel(m, tea.KeyMsg{Type: tea.KeyEsc}, keyPress("enter"))
+	want = []string{"▾ org/a", "#1", "#2", "▾ org/b", "#3"}
+	if got := listTitles(m); !reflect.DeepEqual(got, want) {
+		t.Errorf("items after expand = %q, want %q", got, want)
+	}
+}
+
+func TestModelFilterKeepsSelection(t *testing.T) {
+	m := updateModel(newTestModel(), keyPress("down"), keyPress(" "))
+	m.runPrompt(promptQuery, "repo:org/b")
+
+	want := []string{"▾ org/b", "#3"}
+	if got := listTitles(m); !reflect.DeepEqual(got, want) {
+		t.Errorf("filtered items = %q, want %q", got, want)
+	}
+	if got := targetNumbers(m); !reflect.DeepEqual(got, []int{1}) {
+		t.Errorf("targets() with the selection filtered out = %v, want [1]", got)
+	}
+}
+
+func TestModelCopyWithoutTargets(t *testing.T) {
+	m := updateModel(newTestModel(), keyPress("y"))
+	if view := m.View(); !strings.Contains(view, "no PRs to copy") {
+		t.Errorf("View() does not say there is nothing to copy:\n%s", view)
+	}
+}
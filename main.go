@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
 )
@@ -9,6 +10,10 @@ func main() {
 	cmd := rootCmd()
 	if err := cmd.Execute(); err != nil {
 		fmt.Fprintf(os.Stderr, "%s\n", err)
+		var gateErr *gateError
+		if errors.As(err, &gateErr) {
+			os.Exit(exitCodeGateFailed)
+		}
 		os.Exit(1)
 	}
 }
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+var markdownCellReplacer = strings.NewReplacer("|", `\|`, "\r\n", " ", "\n", " ")
+
+func markdownCheckStatus(pri *PullRequestItem) string {
+	switch pri.CheckStatus {
+	case checkStatusSuccess:
+		return "✅"
+	case checkStatusFailure:
+		return "❌"
+	case checkStatusPending:
+		return "⏳"
+	default:
+		return ""
+	}
+}
+
+func markdownReviewDecision(pri *PullRequestItem) string {
+	switch pri.ReviewDecision {
+	case reviewDecisionApproved:
+		return "👍"
+	case reviewDecisionChangesRequested:
+		return "🛑"
+	case reviewDecisionReviewRequired:
+		return "👀"
+	default:
+		return ""
+	}
+}
+
+// writeMarkdown writes a heading per repository followed by a table of its
+// pull requests, ready to paste into an issue, a wiki page or a standup
+// doc. withState adds a state column after the PR. Authors are written
+// without "@" so that pasting does not mention them.
+func writeMarkdown(w io.Writer, repositories []RepositoryItem, withState bool) error {
+	header := "| PR | Title | Author | Updated | Checks | Review |"
+	separator := "| --- | --- | --- | --- | --- | --- |"
+	if withState {
+		header = "| PR | State | Title | Author | Updated | Checks | Review |"
+		separator = "| --- | --- | --- | --- | --- | --- | --- |"
+	}
+
+	for i, repo := range repositories {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "## %s\n\n", markdownLink(repo.Name, repo.Url))
+		fmt.Fprintln(w, header)
+		fmt.Fprintln(w, separator)
+		for _, pr := range repo.PullRequestItems {
+			title := pr.Title
+			if pr.IsDraft {
+				title += " (draft)"
+			}
+			cells := []string{markdownLink(fmt.Sprintf("#%d", pr.Number), pr.Url)}
+			if withState {
+				cells = append(cells, formatState(&pr))
+			}
+			cells = append(cells,
+				markdownCellReplacer.Replace(title),
+				pr.Author,
+				pr.UpdatedAt.In(time.Local).Format("2006-01-02"),
+				markdownCheckStatus(&pr),
+				markdownReviewDecision(&pr),
+			)
+			if _, err := fmt.Fprintf(w, "| %s |\n", strings.Join(cells, " | ")); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
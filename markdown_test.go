@@ -0,0 +1,96 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteMarkdown(t *testing.T) {
+	updatedAt := time.Date(2024, 11, 30, 12, 0, 0, 0, time.UTC)
+	repositories := []RepositoryItem{
+		{
+			Name: "test/repo",
+			Url:  "https://github.com/test/repo",
+			PullRequestItems: []PullRequestItem{
+				{
+					Number:         2,
+					Title:          "Support a | b",
+					Author:         "alice",
+					UpdatedAt:      updatedAt,
+					Url:            "https://github.com/test/repo/pull/2",
+					CheckStatus:    checkStatusFailure,
+					ReviewDecision: reviewDecisionApproved,
+				},
+				{
+					Number:      1,
+					Title:       "WIP",
+					Author:      "bob",
+					UpdatedAt:   updatedAt,
+					IsDraft:     true,
+					Url:         "https://github.com/test/repo/pull/1",
+					CheckStatus: checkStatusUnknown,
+				},
+			},
+		},
+		{
+			Name: "test/other",
+			Url:  "https://github.com/test/other",
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := writeMarkdown(&buf, repositories, false); err != nil {
+		t.Fatalf("writeMarkdown() returned error: %v", err)
+	}
+	result := buf.String()
+
+	date := updatedAt.In(time.Local).Format("2006-01-02")
+	wantContains := []string{
+		"## [test/repo](https://github.com/test/repo)\n",
+		"| PR | Title | Author | Updated | Checks | Review |\n",
+		"| [#2](https://github.com/test/repo/pull/2) | Support a \\| b | alice | " + date + " | ❌ | 👍 |\n",
+		"| [#1](https://github.com/test/repo/pull/1) | WIP (draft) | bob | " + date + " |  |  |\n",
+		"\n## [test/other](https://github.com/test/other)\n",
+	}
+	for _, want := range wantContains {
+		if !strings.Contains(result, want) {
+			t.Errorf("writeMarkdown() = %q, want to contain %q", result, want)
+		}
+	}
+	if strings.Contains(result, "@alice") {
+		t.Errorf("writeMarkdown() = %q, should not mention authors", result)
+	}
+}
+
+func TestWriteMarkdownWithState(t *testing.T) {
+	updatedAt := time.Date(2024, 11, 30, 12, 0, 0, 0, time.UTC)
+	repositories := []RepositoryItem{
+		{
+			Name: "test/repo",
+			Url:  "https://github.com/test/repo",
+			PullRequestItems: []PullRequestItem{
+				{Number: 3, Title: "Done", Author: "alice", UpdatedAt: updatedAt, Url: "https://github.com/test/repo/pull/3", State: pullRequestStateMerged, CheckStatus: checkStatusSuccess},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := writeMarkdown(&buf, repositories, true); err != nil {
+		t.Fatalf("writeMarkdown() returned error: %v", err)
+	}
+	result := buf.String()
+
+	date := updatedAt.In(time.Local).Format("2006-01-02")
+	wantContains := []string{
+		"| PR | State | Title | Author | Updated | Checks | Review |\n",
+		"| --- | --- | --- | --- | --- | --- | --- |\n",
+		"| [#3](https://github.com/test/repo/pull/3) | merged | Done | alice | " + date + " | ✅ |  |\n",
+	}
+	for _, want := range wantContains {
+		if !strings.Contains(result, want) {
+			t.Errorf("writeMarkdown() = %q, want to contain %q", result, want)
+		}
+	}
+}
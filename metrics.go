@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var metricLabelReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+type metricLabels struct {
+	repository     string
+	author         string
+	draft          bool
+	checkStatus    string
+	reviewDecision string
+}
+
+func (l metricLabels) String() string {
+	return fmt.Sprintf(`repository="%s",author="%s",draft="%t",check_status="%s",review_decision="%s"`,
+		metricLabelReplacer.Replace(l.repository),
+		metricLabelReplacer.Replace(l.author),
+		l.draft,
+		metricLabelReplacer.Replace(l.checkStatus),
+		metricLabelReplacer.Replace(l.reviewDecision),
+	)
+}
+
+// writeMetrics writes gauges derived from repositories in the Prometheus
+// text exposition format.
+func writeMetrics(w io.Writer, repositories []RepositoryItem, now time.Time) error {
+	counts := map[metricLabels]int{}
+	oldest := map[string]time.Time{}
+	for _, repo := range repositories {
+		for _, pr := range repo.PullRequestItems {
+			labels := metricLabels{
+				repository:     pr.RepositoryName,
+				author:         pr.Author,
+				draft:          pr.IsDraft,
+				checkStatus:    pr.CheckStatus,
+				reviewDecision: reviewDecisionOf(&pr),
+			}
+			counts[labels]++
+
+			if created, ok := oldest[pr.RepositoryName]; !ok || pr.CreatedAt.Before(created) {
+				oldest[pr.RepositoryName] = pr.CreatedAt
+			}
+		}
+	}
+
+	lines := make([]string, 0, len(counts))
+	for labels, count := range counts {
+		lines = append(lines, fmt.Sprintf("gh_list_prs_pull_requests{%s} %d\n", labels, count))
+	}
+	sort.Strings(lines)
+
+	fmt.Fprintln(w, "# HELP gh_list_prs_pull_requests Number of pull requests found by the search.")
+	fmt.Fprintln(w, "# TYPE gh_list_prs_pull_requests gauge")
+	for _, line := range lines {
+		fmt.Fprint(w, line)
+	}
+
+	names := make([]string, 0, len(oldest))
+	for name := range oldest {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintln(w, "# HELP gh_list_prs_oldest_pull_request_age_seconds Age of the oldest pull request per repository.")
+	fmt.Fprintln(w, "# TYPE gh_list_prs_oldest_pull_request_age_seconds gauge")
+	for _, name := range names {
+		age := now.Sub(oldest[name]).Seconds()
+		fmt.Fprintf(w, "gh_list_prs_oldest_pull_request_age_seconds{repository=\"%s\"} %s\n", metricLabelReplacer.Replace(name), strconv.FormatFloat(age, 'f', -1, 64))
+	}
+
+	fmt.Fprintln(w, "# HELP gh_list_prs_last_refresh_timestamp_seconds Unix time of the last successful search.")
+	fmt.Fprintln(w, "# TYPE gh_list_prs_last_refresh_timestamp_seconds gauge")
+	_, err := fmt.Fprintf(w, "gh_list_prs_last_refresh_timestamp_seconds %d\n", now.Unix())
+	return err
+}
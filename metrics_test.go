@@ -0,0 +1,42 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteMetrics(t *testing.T) {
+	now := time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)
+	repositories := []RepositoryItem{
+		{
+			Name: "org/a",
+			PullRequestItems: []PullRequestItem{
+				{Number: 3, Author: "alice", RepositoryName: "org/a", CreatedAt: now.Add(-time.Hour), CheckStatus: checkStatusFailure},
+				{Number: 2, Author: "alice", RepositoryName: "org/a", CreatedAt: now.Add(-2 * time.Hour), CheckStatus: checkStatusFailure},
+				{Number: 1, Author: "bo\"b", RepositoryName: "org/a", CreatedAt: now.Add(-24 * time.Hour), CheckStatus: checkStatusSuccess, IsDraft: true, ReviewDecision: reviewDecisionApproved},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := writeMetrics(&buf, repositories, now); err != nil {
+		t.Fatalf("writeMetrics() returned error: %v", err)
+	}
+	result := buf.String()
+
+	wantContains := []string{
+		"# TYPE gh_list_prs_pull_requests gauge\n",
+		`gh_list_prs_pull_requests{repository="org/a",author="alice",draft="false",check_status="FAILURE",review_decision="NONE"} 2` + "\n",
+		`gh_list_prs_pull_requests{repository="org/a",author="bo\"b",draft="true",check_status="SUCCESS",review_decision="APPROVED"} 1` + "\n",
+		"# TYPE gh_list_prs_oldest_pull_request_age_seconds gauge\n",
+		`gh_list_prs_oldest_pull_request_age_seconds{repository="org/a"} 86400` + "\n",
+		"gh_list_prs_last_refresh_timestamp_seconds 1735603200\n",
+	}
+	for _, want := range wantContains {
+		if !strings.Contains(result, want) {
+			t.Errorf("writeMetrics() = %q, want to contain %q", result, want)
+		}
+	}
+}
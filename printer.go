@@ -2,45 +2,208 @@ package main
 
 import (
 	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/term"
+	"github.com/mattn/go-runewidth"
 )
 
-type columnWidths struct {
-	number    int
-	author    int
-	updatedAt int
+// minTitleWidth is the width titles are never truncated below, even when
+// the line overflows the terminal.
+const minTitleWidth = 20
+
+// columnFormats formats each column selectable with --columns.
+var columnFormats = map[string]func(formatter Formatter, pri *PullRequestItem) string{
+	"number": Formatter.FormatPRNumber,
+	"repo": func(formatter Formatter, pri *PullRequestItem) string {
+		return formatter.FormatRepositoryName(pri.RepositoryName, pri.RepositoryUrl)
+	},
+	"author":  Formatter.FormatAuthor,
+	"updated": Formatter.FormatUpdatedAt,
+	"created": Formatter.FormatCreatedAt,
+	"merged":  Formatter.FormatMergedAt,
+	"state":   Formatter.FormatState,
+	"title":   Formatter.FormatTitle,
+	"checks":  Formatter.FormatCheckStatus,
+	"review":  Formatter.FormatReviewDecision,
+	"labels":  Formatter.FormatLabels,
+	"size":    Formatter.FormatSize,
+}
+
+var defaultColumns = []string{"number", "author", "updated", "title", "checks", "review"}
+
+func validateColumns(columns []string) error {
+	if len(columns) == 0 {
+		return fmt.Errorf("no columns selected")
+	}
+	for _, column := range columns {
+		if _, ok := columnFormats[column]; !ok {
+			return fmt.Errorf("invalid column: %s", column)
+		}
+	}
+	return nil
+}
+
+// columnWidths holds the display width of each selected column, measured
+// on the uncolored text.
+type columnWidths []int
+
+// displayWidth returns the number of terminal cells s occupies, counting
+// East Asian wide characters and emoji as two.
+func displayWidth(s string) int {
+	return runewidth.StringWidth(s)
+}
+
+// terminalWidth returns the width of the terminal stdout is attached to, or
+// 0 when stdout is not a terminal.
+func terminalWidth() int {
+	t := term.FromEnv()
+	if !t.IsTerminalOutput() {
+		return 0
+	}
+	width, _, err := t.Size()
+	if err != nil || width <= 0 {
+		return 0
+	}
+	return width
+}
+
+func computeColumnWidths(columns []string, pullRequests []PullRequestItem) columnWidths {
+	plain := &NoColorFormatter{}
+	widths := make(columnWidths, len(columns))
+	for _, pr := range pullRequests {
+		for i, column := range columns {
+			if width := displayWidth(columnFormats[column](plain, &pr)); width > widths[i] {
+				widths[i] = width
+			}
+		}
+	}
+	return widths
+}
+
+// untilTitle returns a copy of w with the widths of the title and the
+// columns after it cleared, so that only the columns before the title are
+// padded as in the default layout.
+func (w columnWidths) untilTitle(columns []string) columnWidths {
+	widths := slices.Clone(w)
+	if i := slices.Index(columns, "title"); i >= 0 {
+		clear(widths[i:])
+	}
+	return widths
+}
+
+// formatLine formats the columns of pri separated by a space. Every column
+// but the last is padded to its width.
+func (pri *PullRequestItem) formatLine(columns []string, widths columnWidths, formatter Formatter) string {
+	plain := &NoColorFormatter{}
+	var line strings.Builder
+	for i, column := range columns {
+		format := columnFormats[column]
+		if i > 0 {
+			line.WriteString(" ")
+		}
+		line.WriteString(format(formatter, pri))
+		if i < len(columns)-1 {
+			line.WriteString(strings.Repeat(" ", max(widths[i]-displayWidth(format(plain, pri)), 0)))
+		}
+	}
+	return strings.TrimRight(line.String(), " ")
 }
 
-func (pri *PullRequestItem) printLine(widths columnWidths, formatter Formatter) {
-	numberString := formatter.FormatPRNumber(pri)
-	numberPadding := widths.number - len(fmt.Sprintf("#%d", pri.Number))
-	login := formatter.FormatAuthor(pri)
-	updatedAt := formatter.FormatUpdatedAt(pri)
-	title := formatter.FormatTitle(pri)
-	statusSymbol := formatter.FormatCheckStatus(pri)
-	reviewDecision := formatter.FormatReviewDecision(pri)
+// fitToWidth truncates titles with an ellipsis so that lines fit in
+// maxWidth cells. It returns truncated copies of pullRequests and their
+// column widths. A maxWidth of 0 disables truncation.
+func fitToWidth(columns []string, pullRequests []PullRequestItem, maxWidth int) ([]PullRequestItem, columnWidths) {
+	widths := computeColumnWidths(columns, pullRequests)
+	titleIndex := slices.Index(columns, "title")
+	if maxWidth <= 0 || titleIndex < 0 {
+		return pullRequests, widths
+	}
 
-	fmt.Printf("%s%-*s%-*s %-*s %s %s %s\n", numberString, numberPadding+1, "", widths.author, login, widths.updatedAt, updatedAt, title, statusSymbol, reviewDecision)
+	lineWidth := len(columns) - 1
+	for _, width := range widths {
+		lineWidth += width
+	}
+	if lineWidth <= maxWidth {
+		return pullRequests, widths
+	}
+	titleWidth := max(widths[titleIndex]-(lineWidth-maxWidth), minTitleWidth)
+
+	plain := &NoColorFormatter{}
+	fitted := make([]PullRequestItem, len(pullRequests))
+	for i, pr := range pullRequests {
+		// Leave room for the decorations the formatter adds, e.g. " (draft)".
+		decoration := displayWidth(plain.FormatTitle(&pr)) - displayWidth(pr.Title)
+		if displayWidth(pr.Title)+decoration > titleWidth {
+			pr.Title = runewidth.Truncate(pr.Title, max(titleWidth-decoration, 1), "…")
+		}
+		fitted[i] = pr
+	}
+	return fitted, computeColumnWidths(columns, fitted)
 }
 
-func (ri *RepositoryItem) printList(opts *Options) {
-	formatter := NewFormatter(opts.NoColor)
+func (ri *RepositoryItem) printList(w io.Writer, pullRequests []PullRequestItem, columns []string, widths columnWidths, formatter Formatter) {
+	fmt.Fprintf(w, "# %s\n", formatter.FormatRepositoryName(ri.Name, ri.Url))
 
-	widths := columnWidths{updatedAt: len("2006-01-02")}
-	for _, pr := range ri.PullRequestItems {
-		nWidth := len(fmt.Sprintf("#%d", pr.Number))
-		if nWidth > widths.number {
-			widths.number = nWidth
+	for _, pr := range pullRequests {
+		fmt.Fprintln(w, pr.formatLine(columns, widths, formatter))
+	}
+}
+
+// writePlain writes a list per repository, each followed by a blank line.
+// With opts.AlignGlobal the columns line up across all repositories rather
+// than within each one, and with opts.AlignAll the columns from the title
+// on line up too.
+func writePlain(w io.Writer, repositories []RepositoryItem, opts *Options, maxWidth int) {
+	formatter := NewFormatter(opts.NoColor, &opts.Config.Theme)
+	fit := func(pullRequests []PullRequestItem) ([]PullRequestItem, columnWidths) {
+		fitted, widths := fitToWidth(opts.Columns, pullRequests, maxWidth)
+		if !opts.AlignAll {
+			widths = widths.untilTitle(opts.Columns)
 		}
+		return fitted, widths
+	}
 
-		aWidth := len(pr.Author)
-		if aWidth > widths.author {
-			widths.author = aWidth
+	if !opts.AlignGlobal {
+		for _, repo := range repositories {
+			pullRequests, widths := fit(repo.PullRequestItems)
+			repo.printList(w, pullRequests, opts.Columns, widths, formatter)
+			fmt.Fprintln(w)
 		}
+		return
 	}
 
-	fmt.Printf("# %s\n", formatter.FormatRepositoryName(ri.Name))
+	pullRequests, widths := fit(allPullRequests(repositories))
+	for _, repo := range repositories {
+		n := len(repo.PullRequestItems)
+		repo.printList(w, pullRequests[:n], opts.Columns, widths, formatter)
+		fmt.Fprintln(w)
+		pullRequests = pullRequests[n:]
+	}
+}
+
+// writeTable writes the pull requests of all repositories as one table with
+// a repo column, followed by a blank line.
+func writeTable(w io.Writer, repositories []RepositoryItem, opts *Options, maxWidth int) {
+	formatter := NewFormatter(opts.NoColor, &opts.Config.Theme)
+	columns := opts.Columns
+	if !slices.Contains(columns, "repo") {
+		columns = append([]string{"repo"}, columns...)
+	}
+
+	pullRequests, widths := fitToWidth(columns, allPullRequests(repositories), maxWidth)
+	for _, pr := range pullRequests {
+		fmt.Fprintln(w, pr.formatLine(columns, widths, formatter))
+	}
+	fmt.Fprintln(w)
+}
 
-	for _, pr := range ri.PullRequestItems {
-		pr.printLine(widths, formatter)
+func allPullRequests(repositories []RepositoryItem) []PullRequestItem {
+	pullRequests := []PullRequestItem{}
+	for _, repo := range repositories {
+		pullRequests = append(pullRequests, repo.PullRequestItems...)
 	}
+	return pullRequests
 }
@@ -0,0 +1,251 @@
+package main
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestValidateColumns(t *testing.T) {
+	tests := []struct {
+		columns []string
+		wantErr bool
+	}{
+		{defaultColumns, false},
+		{[]string{"repo", "number", "size", "labels", "created"}, false},
+		{[]string{}, true},
+		{[]string{"number", "milestone"}, true},
+	}
+
+	for _, tt := range tests {
+		if err := validateColumns(tt.columns); (err != nil) != tt.wantErr {
+			t.Errorf("validateColumns(%q) error = %v, wantErr %v", tt.columns, err, tt.wantErr)
+		}
+	}
+}
+
+func TestComputeColumnWidths(t *testing.T) {
+	pullRequests := []PullRequestItem{
+		{Number: 7, Author: "alice", RepositoryName: "org/a", Additions: 1, Deletions: 0},
+		{Number: 123, Author: "bob", RepositoryName: "org/long-name", Additions: 1000, Deletions: 20},
+	}
+
+	got := computeColumnWidths([]string{"number", "author", "repo", "size"}, pullRequests)
+	want := columnWidths{4, 5, 13, 9}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("computeColumnWidths() = %v, want %v", got, want)
+	}
+}
+
+func TestFormatLine(t *testing.T) {
+	updatedAt := time.Date(2024, 11, 30, 12, 0, 0, 0, time.UTC)
+	date := updatedAt.In(time.Local).Format("2006-01-02")
+	pullRequests := []PullRequestItem{
+		{Number: 7, Author: "alice", Title: "Fix", UpdatedAt: updatedAt, CheckStatus: checkStatusSuccess},
+		{Number: 123, Author: "bob", Title: "Add feature", UpdatedAt: updatedAt, CheckStatus: checkStatusFailure, ReviewDecision: reviewDecisionApproved},
+	}
+
+	tests := []struct {
+		name       string
+		columns    []string
+		untilTitle bool
+		want       []string
+	}{
+		{
+			name:       "default layout",
+			columns:    defaultColumns,
+			untilTitle: true,
+			want: []string{
+				"#7   alice " + date + " Fix ✔",
+				"#123 bob   " + date + " Add feature ✘ ✓",
+			},
+		},
+		{
+			name:    "default columns aligned",
+			columns: defaultColumns,
+			want: []string{
+				"#7   alice " + date + " Fix         ✔",
+				"#123 bob   " + date + " Add feature ✘ ✓",
+			},
+		},
+		{
+			name:    "reordered columns",
+			columns: []string{"title", "number"},
+			want: []string{
+				"Fix         #7",
+				"Add feature #123",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			widths := computeColumnWidths(tt.columns, pullRequests)
+			if tt.untilTitle {
+				widths = widths.untilTitle(tt.columns)
+			}
+			for i, pr := range pullRequests {
+				if got := pr.formatLine(tt.columns, widths, &NoColorFormatter{}); got != tt.want[i] {
+					t.Errorf("formatLine() = %q, want %q", got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestFormatLineWideCharacters(t *testing.T) {
+	pullRequests := []PullRequestItem{
+		{Number: 1, Author: "山田", Title: "日本語のタイトル", CheckStatus: checkStatusPending},
+		{Number: 2, Author: "alice", Title: "Fix 🐛", CheckStatus: checkStatusSuccess},
+	}
+	columns := []string{"author", "title", "checks", "number"}
+	want := []string{
+		"山田  日本語のタイトル ⏳ #1",
+		"alice Fix 🐛           ✔  #2",
+	}
+
+	widths := computeColumnWidths(columns, pullRequests)
+	for i, pr := range pullRequests {
+		if got := pr.formatLine(columns, widths, &NoColorFormatter{}); got != want[i] {
+			t.Errorf("formatLine() = %q, want %q", got, want[i])
+		}
+	}
+}
+
+func TestFitToWidth(t *testing.T) {
+	pullRequests := []PullRequestItem{
+		{Number: 1, Title: "Short"},
+		{Number: 2, Title: "A rather long title that does not fit"},
+		{Number: 3, Title: "Another long title in a draft", IsDraft: true},
+		{Number: 4, Title: "日本語のとても長いタイトルです"},
+	}
+	columns := []string{"number", "title"}
+
+	tests := []struct {
+		name      string
+		maxWidth  int
+		want      []string
+		wantWidth int
+	}{
+		{
+			name:      "no limit",
+			maxWidth:  0,
+			want:      []string{"Short", "A rather long title that does not fit", "Another long title in a draft", "日本語のとても長いタイトルです"},
+			wantWidth: 37,
+		},
+		{
+			name:      "fits",
+			maxWidth:  80,
+			want:      []string{"Short", "A rather long title that does not fit", "Another long title in a draft", "日本語のとても長いタイトルです"},
+			wantWidth: 37,
+		},
+		{
+			name:      "truncated",
+			maxWidth:  28,
+			want:      []string{"Short", "A rather long title that…", "Another long tit…", "日本語のとても長いタイト…"},
+			wantWidth: 25,
+		},
+		{
+			name:      "never below the minimum",
+			maxWidth:  10,
+			want:      []string{"Short", "A rather long title…", "Another lon…", "日本語のとても長い…"},
+			wantWidth: minTitleWidth,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fitted, widths := fitToWidth(columns, pullRequests, tt.maxWidth)
+			for i, pr := range fitted {
+				if pr.Title != tt.want[i] {
+					t.Errorf("Title = %q, want %q", pr.Title, tt.want[i])
+				}
+			}
+			if widths[1] != tt.wantWidth {
+				t.Errorf("title width = %d, want %d", widths[1], tt.wantWidth)
+			}
+		})
+	}
+	if pullRequests[1].Title != "A rather long title that does not fit" {
+		t.Errorf("fitToWidth modified its input: %q", pullRequests[1].Title)
+	}
+}
+
+func TestWritePlain(t *testing.T) {
+	repositories := []RepositoryItem{
+		{Name: "org/a", PullRequestItems: []PullRequestItem{{Number: 1, Author: "al", Title: "One", RepositoryName: "org/a", CheckStatus: checkStatusSuccess}}},
+		{Name: "org/b", PullRequestItems: []PullRequestItem{{Number: 100, Author: "carol", Title: "Second", RepositoryName: "org/b", CheckStatus: checkStatusFailure}}},
+	}
+	columns := []string{"number", "author", "title", "checks"}
+
+	tests := []struct {
+		name        string
+		alignGlobal bool
+		alignAll    bool
+		want        string
+	}{
+		{
+			name:        "aligned globally",
+			alignGlobal: true,
+			want:        "# org/a\n#1   al    One ✔\n\n# org/b\n#100 carol Second ✘\n\n",
+		},
+		{
+			name:        "aligned per repository",
+			alignGlobal: false,
+			want:        "# org/a\n#1 al One ✔\n\n# org/b\n#100 carol Second ✘\n\n",
+		},
+		{
+			name:        "all columns aligned",
+			alignGlobal: true,
+			alignAll:    true,
+			want:        "# org/a\n#1   al    One    ✔\n\n# org/b\n#100 carol Second ✘\n\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := &Options{Columns: columns, NoColor: true, AlignGlobal: tt.alignGlobal, AlignAll: tt.alignAll, Config: &Config{}}
+			var buf bytes.Buffer
+			writePlain(&buf, repositories, opts, 0)
+			if got := buf.String(); got != tt.want {
+				t.Errorf("writePlain() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteTable(t *testing.T) {
+	repositories := []RepositoryItem{
+		{Name: "org/a", PullRequestItems: []PullRequestItem{{Number: 1, Author: "al", Title: "One", RepositoryName: "org/a"}}},
+		{Name: "org/bb", PullRequestItems: []PullRequestItem{{Number: 100, Author: "carol", Title: "Two", RepositoryName: "org/bb"}}},
+	}
+
+	tests := []struct {
+		name    string
+		columns []string
+		want    string
+	}{
+		{
+			name:    "repo column prepended",
+			columns: []string{"number", "author", "title"},
+			want:    "org/a  #1   al    One\norg/bb #100 carol Two\n\n",
+		},
+		{
+			name:    "repo column kept in place",
+			columns: []string{"number", "repo", "title"},
+			want:    "#1   org/a  One\n#100 org/bb Two\n\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := &Options{Columns: tt.columns, NoColor: true, Config: &Config{}}
+			var buf bytes.Buffer
+			writeTable(&buf, repositories, opts, 0)
+			if got := buf.String(); got != tt.want {
+				t.Errorf("writeTable() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// repoPattern matches repositories by name. "re:<regexp>" matches the full
+// "owner/repo" name anywhere; other patterns are globs matching the full
+// name when they contain "/", and the repository name alone otherwise.
+type repoPattern struct {
+	re   *regexp.Regexp
+	glob string
+}
+
+// isRepoPattern reports whether an --exclude or --include-repo value is a
+// pattern, filtered client-side, rather than a repository name, which goes
+// in the search query.
+func isRepoPattern(s string) bool {
+	return strings.HasPrefix(s, "re:") || strings.ContainsAny(s, "*?[")
+}
+
+func compileRepoPattern(s string) (repoPattern, error) {
+	if expr, ok := strings.CutPrefix(s, "re:"); ok {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return repoPattern{}, fmt.Errorf("invalid repository pattern %q: %w", s, err)
+		}
+		return repoPattern{re: re}, nil
+	}
+	if _, err := path.Match(s, ""); err != nil {
+		return repoPattern{}, fmt.Errorf("invalid repository pattern %q: %w", s, err)
+	}
+	return repoPattern{glob: s}, nil
+}
+
+// namePattern matches the repository named name, "repo" in any owner or
+// "owner/repo". Like repo: in a search, the name matches case-insensitively.
+func namePattern(name string) repoPattern {
+	expr := "(?i)^" + regexp.QuoteMeta(name) + "$"
+	if !strings.Contains(name, "/") {
+		expr = "(?i)^[^/]+/" + regexp.QuoteMeta(name) + "$"
+	}
+	return repoPattern{re: regexp.MustCompile(expr)}
+}
+
+func (p repoPattern) matches(nameWithOwner string) bool {
+	if p.re != nil {
+		return p.re.MatchString(nameWithOwner)
+	}
+	name := nameWithOwner
+	if !strings.Contains(p.glob, "/") {
+		name = path.Base(nameWithOwner)
+	}
+	ok, _ := path.Match(p.glob, name)
+	return ok
+}
+
+// repoFilter drops repositories matching an exclude pattern, and, when
+// there are includes, those matching none of them.
+type repoFilter struct {
+	excludes []repoPattern
+	includes []repoPattern
+}
+
+// newRepoFilter compiles the --exclude patterns, leaving out plain names,
+// and all --include-repo values. Included names are searched with repo:
+// qualifiers, but are kept here too, as a search for the patterns may find
+// them as well.
+func newRepoFilter(excludes []string, includes []string) (*repoFilter, error) {
+	f := &repoFilter{}
+	for _, exclude := range excludes {
+		if !isRepoPattern(exclude) {
+			continue
+		}
+		p, err := compileRepoPattern(exclude)
+		if err != nil {
+			return nil, err
+		}
+		f.excludes = append(f.excludes, p)
+	}
+	for _, include := range includes {
+		if !isRepoPattern(include) {
+			f.includes = append(f.includes, namePattern(include))
+			continue
+		}
+		p, err := compileRepoPattern(include)
+		if err != nil {
+			return nil, err
+		}
+		f.includes = append(f.includes, p)
+	}
+	return f, nil
+}
+
+// withExcludedNames returns a copy of f also excluding the repositories
+// named "owner/repo" in names.
+func (f *repoFilter) withExcludedNames(names []string) *repoFilter {
+	excludes := slices.Clone(f.excludes)
+	for _, name := range names {
+		excludes = append(excludes, namePattern(name))
+	}
+	return &repoFilter{excludes: excludes, includes: f.includes}
+}
+
+func (f *repoFilter) keep(nameWithOwner string) bool {
+	for _, p := range f.excludes {
+		if p.matches(nameWithOwner) {
+			return false
+		}
+	}
+	if len(f.includes) == 0 {
+		return true
+	}
+	for _, p := range f.includes {
+		if p.matches(nameWithOwner) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *repoFilter) apply(repositories []RepositoryItem) []RepositoryItem {
+	kept := make([]RepositoryItem, 0, len(repositories))
+	for _, repo := range repositories {
+		if f.keep(repo.Name) {
+			kept = append(kept, repo)
+		}
+	}
+	return kept
+}
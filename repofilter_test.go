@@ -0,0 +1,122 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestIsRepoPattern(t *testing.T) {
+	tests := map[string]bool{
+		"repo1":          false,
+		"org/repo1":      false,
+		"*-archive":      true,
+		"org/legacy-?":   true,
+		"[ab]-service":   true,
+		"re:^org/legacy": true,
+	}
+
+	for s, want := range tests {
+		if got := isRepoPattern(s); got != want {
+			t.Errorf("isRepoPattern(%q) = %v, want %v", s, got, want)
+		}
+	}
+}
+
+func TestRepoPatternMatches(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{"*-archive", "org/web-archive", true},
+		{"*-archive", "org/archive-tools", false},
+		{"org/*-archive", "org/web-archive", true},
+		{"org/*-archive", "other/web-archive", false},
+		{"repo1", "org/repo1", true},
+		{"org/repo1", "org/repo1", true},
+		{"org/repo1", "org/repo10", false},
+		{"re:^org/legacy-", "org/legacy-api", true},
+		{"re:^org/legacy-", "org/new-legacy-api", false},
+		{"re:(?i)DEPRECATED", "org/deprecated-ui", true},
+	}
+
+	for _, tt := range tests {
+		p, err := compileRepoPattern(tt.pattern)
+		if err != nil {
+			t.Fatalf("compileRepoPattern(%q) error = %v", tt.pattern, err)
+		}
+		if got := p.matches(tt.name); got != tt.want {
+			t.Errorf("%q matches %q = %v, want %v", tt.pattern, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCompileRepoPatternInvalid(t *testing.T) {
+	for _, pattern := range []string{"re:(", "[a-"} {
+		if _, err := compileRepoPattern(pattern); err == nil {
+			t.Errorf("compileRepoPattern(%q) returned no error", pattern)
+		}
+	}
+}
+
+func TestRepoFilterApply(t *testing.T) {
+	repositories := []RepositoryItem{
+		{Name: "org/api"},
+		{Name: "org/api-archive"},
+		{Name: "org/web"},
+		{Name: "org/worker"},
+	}
+
+	tests := []struct {
+		name     string
+		excludes []string
+		includes []string
+		want     []string
+	}{
+		{"no filter", nil, nil, []string{"org/api", "org/api-archive", "org/web", "org/worker"}},
+		{"exact excludes are left to the query", []string{"api"}, nil, []string{"org/api", "org/api-archive", "org/web", "org/worker"}},
+		{"glob exclude", []string{"*-archive"}, nil, []string{"org/api", "org/web", "org/worker"}},
+		{"includes", nil, []string{"api*", "org/web"}, []string{"org/api", "org/api-archive", "org/web"}},
+		{"included names ignore case", nil, []string{"API", "Org/Web"}, []string{"org/api", "org/web"}},
+		{"includes and excludes", []string{"re:archive$"}, []string{"re:^org/(api|worker)"}, []string{"org/api", "org/worker"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := newRepoFilter(tt.excludes, tt.includes)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := []string{}
+			for _, repo := range filter.apply(repositories) {
+				got = append(got, repo.Name)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("apply() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRepoFilterWithExcludedNames(t *testing.T) {
+	f, err := newRepoFilter([]string{"*-archive"}, nil)
+	if err != nil {
+		t.Fatalf("newRepoFilter() error = %v", err)
+	}
+	g := f.withExcludedNames([]string{"org/Repo1"})
+
+	tests := map[string]bool{
+		"org/repo1":       false,
+		"org/repo10":      true,
+		"other/repo1":     true,
+		"org/web-archive": false,
+	}
+	for name, want := range tests {
+		if got := g.keep(name); got != want {
+			t.Errorf("keep(%q) = %v, want %v", name, got, want)
+		}
+	}
+	if !f.keep("org/repo1") {
+		t.Errorf("withExcludedNames() changed the original filter")
+	}
+}
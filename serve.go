@@ -0,0 +1,174 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+type apiResponse struct {
+	FetchedAt    time.Time        `json:"fetched_at"`
+	Repositories []RepositoryItem `json:"repositories"`
+}
+
+// renderedPage is a response body with its ETag.
+type renderedPage struct {
+	body []byte
+	etag string
+}
+
+// newRenderedPage returns body with an ETag derived from content, the data
+// body is rendered from without timestamps that change on every refresh.
+func newRenderedPage(body []byte, content []byte) renderedPage {
+	sum := sha256.Sum256(content)
+	return renderedPage{body: body, etag: `"` + hex.EncodeToString(sum[:16]) + `"`}
+}
+
+// dashboardServer serves the latest search result as an HTML dashboard, as
+// JSON and as Prometheus metrics.
+type dashboardServer struct {
+	// interval is how often the dashboard reloads itself.
+	interval time.Duration
+
+	mu      sync.RWMutex
+	html    *renderedPage
+	json    *renderedPage
+	metrics *renderedPage
+}
+
+// update renders repositories fetched at now and serves them from then on.
+func (s *dashboardServer) update(repositories []RepositoryItem, now time.Time) error {
+	report := newHTMLReport(repositories, now)
+	report.Refresh = int(s.interval / time.Second)
+	var htmlBody bytes.Buffer
+	if err := writeHTMLReport(&htmlBody, report); err != nil {
+		return err
+	}
+	report.GeneratedAt = time.Time{}
+	htmlContent, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+
+	if repositories == nil {
+		repositories = []RepositoryItem{}
+	}
+	jsonBody, err := json.Marshal(apiResponse{FetchedAt: now, Repositories: repositories})
+	if err != nil {
+		return err
+	}
+	jsonContent, err := json.Marshal(repositories)
+	if err != nil {
+		return err
+	}
+
+	var metricsBody bytes.Buffer
+	if err := writeMetrics(&metricsBody, repositories, now); err != nil {
+		return err
+	}
+
+	htmlPage := newRenderedPage(htmlBody.Bytes(), htmlContent)
+	jsonPage := newRenderedPage(jsonBody, jsonContent)
+	// Ages and the refresh time change the metrics on every refresh.
+	metricsPage := newRenderedPage(metricsBody.Bytes(), metricsBody.Bytes())
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.html = &htmlPage
+	s.json = &jsonPage
+	s.metrics = &metricsPage
+	return nil
+}
+
+func (s *dashboardServer) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.RLock()
+		page := s.html
+		s.mu.RUnlock()
+		writePage(w, r, page, "text/html; charset=utf-8")
+	})
+	mux.HandleFunc("GET /api/prs", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.RLock()
+		page := s.json
+		s.mu.RUnlock()
+		writePage(w, r, page, "application/json")
+	})
+	mux.HandleFunc("GET /metrics", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.RLock()
+		page := s.metrics
+		s.mu.RUnlock()
+		writePage(w, r, page, "text/plain; version=0.0.4; charset=utf-8")
+	})
+	return mux
+}
+
+// writePage writes page, or 304 when the client already has it.
+func writePage(w http.ResponseWriter, r *http.Request, page *renderedPage, contentType string) {
+	if page == nil {
+		http.Error(w, "not fetched yet", http.StatusServiceUnavailable)
+		return
+	}
+
+	w.Header().Set("ETag", page.etag)
+	w.Header().Set("Cache-Control", "no-cache")
+	if r.Header.Get("If-None-Match") == page.etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	_, _ = w.Write(page.body)
+}
+
+// refresh runs the search every interval until ctx is done. Failed
+// searches are logged and the previous result keeps being served.
+func (s *dashboardServer) refresh(ctx context.Context, orgs []string, opts *Options, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		repositories, err := fetchRepositories(orgs, opts)
+		if err == nil {
+			err = s.update(repositories, time.Now())
+		}
+		if err != nil {
+			log.Printf("refresh failed: %s", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func serve(ctx context.Context, orgs []string, opts *Options) error {
+	s := &dashboardServer{interval: opts.Interval}
+	go s.refresh(ctx, orgs, opts, opts.Interval)
+
+	server := &http.Server{
+		Addr:              opts.Addr,
+		Handler:           s.handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	log.Printf("serving on %s", opts.Addr)
+	if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
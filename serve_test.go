@@ -0,0 +1,164 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDashboardServerNotReady(t *testing.T) {
+	s := &dashboardServer{}
+
+	for _, path := range []string{"/", "/api/prs", "/metrics"} {
+		rec := httptest.NewRecorder()
+		s.handler().ServeHTTP(rec, httptest.NewRequest("GET", path, nil))
+		if rec.Code != http.StatusServiceUnavailable {
+			t.Errorf("GET %s status = %d, want %d", path, rec.Code, http.StatusServiceUnavailable)
+		}
+	}
+}
+
+func TestDashboardServer(t *testing.T) {
+	now := time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)
+	repositories := []RepositoryItem{
+		{
+			Name: "test/repo",
+			PullRequestItems: []PullRequestItem{
+				{Number: 1, Title: "Fix bug", RepositoryName: "test/repo", Url: "https://github.com/test/repo/pull/1", UpdatedAt: now},
+			},
+		},
+	}
+
+	s := &dashboardServer{}
+	if err := s.update(repositories, now); err != nil {
+		t.Fatalf("update() returned error: %v", err)
+	}
+	handler := s.handler()
+
+	t.Run("dashboard", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+		}
+		if !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/html") {
+			t.Errorf("Content-Type = %q, want text/html", rec.Header().Get("Content-Type"))
+		}
+		if !strings.Contains(rec.Body.String(), "Fix bug") {
+			t.Errorf("body does not contain the pull request title")
+		}
+	})
+
+	t.Run("api", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest("GET", "/api/prs", nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+		}
+
+		var response apiResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+			t.Fatalf("invalid JSON: %v", err)
+		}
+		if !response.FetchedAt.Equal(now) {
+			t.Errorf("fetched_at = %v, want %v", response.FetchedAt, now)
+		}
+		if len(response.Repositories) != 1 || response.Repositories[0].PullRequestItems[0].Title != "Fix bug" {
+			t.Errorf("repositories = %+v, want test/repo with Fix bug", response.Repositories)
+		}
+
+		etag := rec.Header().Get("ETag")
+		if etag == "" {
+			t.Fatal("ETag is empty")
+		}
+
+		req := httptest.NewRequest("GET", "/api/prs", nil)
+		req.Header.Set("If-None-Match", etag)
+		rec = httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != http.StatusNotModified {
+			t.Errorf("status with matching ETag = %d, want %d", rec.Code, http.StatusNotModified)
+		}
+		if rec.Body.Len() != 0 {
+			t.Errorf("body with matching ETag = %q, want empty", rec.Body.String())
+		}
+	})
+
+	t.Run("metrics", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+		}
+		if !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/plain") {
+			t.Errorf("Content-Type = %q, want text/plain", rec.Header().Get("Content-Type"))
+		}
+		if !strings.Contains(rec.Body.String(), `gh_list_prs_pull_requests{repository="test/repo"`) {
+			t.Errorf("body = %q, want pull request gauge for test/repo", rec.Body.String())
+		}
+	})
+
+	t.Run("etag stays while the result does not change", func(t *testing.T) {
+		etags := map[string]string{}
+		for _, path := range []string{"/", "/api/prs"} {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest("GET", path, nil))
+			etags[path] = rec.Header().Get("ETag")
+		}
+
+		if err := s.update(repositories, now.Add(time.Minute)); err != nil {
+			t.Fatalf("update() returned error: %v", err)
+		}
+		for path, etag := range etags {
+			req := httptest.NewRequest("GET", path, nil)
+			req.Header.Set("If-None-Match", etag)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != http.StatusNotModified {
+				t.Errorf("GET %s status after a refresh with the same result = %d, want %d", path, rec.Code, http.StatusNotModified)
+			}
+		}
+	})
+
+	t.Run("etag changes with result", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest("GET", "/api/prs", nil))
+		before := rec.Header().Get("ETag")
+
+		if err := s.update(nil, now.Add(time.Minute)); err != nil {
+			t.Fatalf("update() returned error: %v", err)
+		}
+		rec = httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest("GET", "/api/prs", nil))
+		if rec.Header().Get("ETag") == before {
+			t.Errorf("ETag did not change after update")
+		}
+		if !strings.Contains(rec.Body.String(), `"repositories":[]`) {
+			t.Errorf("body = %q, want empty repositories", rec.Body.String())
+		}
+	})
+
+	t.Run("unknown path", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest("GET", "/unknown", nil))
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("status = %d, want %d", rec.Code, http.StatusNotFound)
+		}
+	})
+}
+
+func TestDashboardServerAutoRefresh(t *testing.T) {
+	s := &dashboardServer{interval: 5 * time.Minute}
+	if err := s.update(nil, time.Now()); err != nil {
+		t.Fatalf("update() returned error: %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	s.handler().ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+	if want := `<meta http-equiv="refresh" content="300">`; !strings.Contains(rec.Body.String(), want) {
+		t.Errorf("dashboard does not contain %q", want)
+	}
+}
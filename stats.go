@@ -0,0 +1,163 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"text/tabwriter"
+	"time"
+)
+
+type countEntry struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+type oldestPullRequest struct {
+	Repository string  `json:"repository"`
+	Number     int     `json:"number"`
+	Title      string  `json:"title"`
+	Url        string  `json:"url"`
+	AgeDays    float64 `json:"age_days"`
+}
+
+// Stats summarizes pull requests. Ages are measured from creation.
+type Stats struct {
+	Total           int                `json:"total"`
+	Drafts          int                `json:"drafts"`
+	DraftRatio      float64            `json:"draft_ratio"`
+	MedianAgeDays   float64            `json:"median_age_days"`
+	P90AgeDays      float64            `json:"p90_age_days"`
+	Oldest          *oldestPullRequest `json:"oldest,omitempty"`
+	Repositories    []countEntry       `json:"repositories"`
+	Authors         []countEntry       `json:"authors"`
+	CheckStatuses   []countEntry       `json:"check_statuses"`
+	ReviewDecisions []countEntry       `json:"review_decisions"`
+}
+
+func computeStats(repositories []RepositoryItem, now time.Time) Stats {
+	stats := Stats{}
+	authors := map[string]int{}
+	checkStatuses := map[string]int{}
+	reviewDecisions := map[string]int{}
+	ages := []float64{}
+
+	for _, repo := range repositories {
+		if len(repo.PullRequestItems) == 0 {
+			continue
+		}
+		stats.Repositories = append(stats.Repositories, countEntry{Name: repo.Name, Count: len(repo.PullRequestItems)})
+
+		for _, pr := range repo.PullRequestItems {
+			stats.Total++
+			if pr.IsDraft {
+				stats.Drafts++
+			}
+			authors[pr.Author]++
+			checkStatuses[pr.CheckStatus]++
+			reviewDecisions[reviewDecisionOf(&pr)]++
+
+			age := now.Sub(pr.CreatedAt).Hours() / 24
+			ages = append(ages, age)
+			if stats.Oldest == nil || age > stats.Oldest.AgeDays {
+				stats.Oldest = &oldestPullRequest{
+					Repository: pr.RepositoryName,
+					Number:     pr.Number,
+					Title:      pr.Title,
+					Url:        pr.Url,
+					AgeDays:    age,
+				}
+			}
+		}
+	}
+
+	if stats.Total > 0 {
+		stats.DraftRatio = float64(stats.Drafts) / float64(stats.Total)
+	}
+	sort.Float64s(ages)
+	stats.MedianAgeDays = percentile(ages, 50)
+	stats.P90AgeDays = percentile(ages, 90)
+
+	sortCounts(stats.Repositories)
+	stats.Authors = countEntries(authors)
+	stats.CheckStatuses = countEntries(checkStatuses)
+	stats.ReviewDecisions = countEntries(reviewDecisions)
+	return stats
+}
+
+// percentile returns the nearest-rank percentile p of sorted values.
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+func countEntries(counts map[string]int) []countEntry {
+	entries := make([]countEntry, 0, len(counts))
+	for name, count := range counts {
+		entries = append(entries, countEntry{Name: name, Count: count})
+	}
+	sortCounts(entries)
+	return entries
+}
+
+// sortCounts sorts entries by count descending, then by name.
+func sortCounts(entries []countEntry) {
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Count != entries[j].Count {
+			return entries[i].Count > entries[j].Count
+		}
+		return entries[i].Name < entries[j].Name
+	})
+}
+
+func formatAgeDays(days float64) string {
+	if days < 1 {
+		return fmt.Sprintf("%dh", int(days*24))
+	}
+	return fmt.Sprintf("%dd", int(days))
+}
+
+// writeStats writes stats as a compact table.
+func writeStats(w io.Writer, stats Stats) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "PRs\t%d\n", stats.Total)
+	fmt.Fprintf(tw, "Drafts\t%d (%.0f%%)\n", stats.Drafts, stats.DraftRatio*100)
+	fmt.Fprintf(tw, "Median age\t%s\n", formatAgeDays(stats.MedianAgeDays))
+	fmt.Fprintf(tw, "P90 age\t%s\n", formatAgeDays(stats.P90AgeDays))
+	if stats.Oldest != nil {
+		fmt.Fprintf(tw, "Oldest\t%s#%d %s (%s)\n", stats.Oldest.Repository, stats.Oldest.Number, stats.Oldest.Title, formatAgeDays(stats.Oldest.AgeDays))
+	}
+
+	sections := []struct {
+		title   string
+		entries []countEntry
+	}{
+		{"Repository", stats.Repositories},
+		{"Author", stats.Authors},
+		{"Check status", stats.CheckStatuses},
+		{"Review decision", stats.ReviewDecisions},
+	}
+	for _, section := range sections {
+		fmt.Fprintf(tw, "\n%s\tPRs\n", section.title)
+		for _, entry := range section.entries {
+			fmt.Fprintf(tw, "%s\t%d\n", entry.Name, entry.Count)
+		}
+	}
+
+	return tw.Flush()
+}
+
+func writeStatsJSON(w io.Writer, stats Stats) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(stats)
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestComputeStats(t *testing.T) {
+	now := time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)
+	newPR := func(repo string, number int, author string, ageDays int) PullRequestItem {
+		return PullRequestItem{
+			Number:         number,
+			Author:         author,
+			RepositoryName: repo,
+			CreatedAt:      now.AddDate(0, 0, -ageDays),
+			CheckStatus:    checkStatusSuccess,
+		}
+	}
+
+	draft := newPR("org/a", 3, "alice", 10)
+	draft.IsDraft = true
+	draft.CheckStatus = checkStatusFailure
+	approved := newPR("org/a", 2, "bob", 2)
+	approved.ReviewDecision = reviewDecisionApproved
+
+	repositories := []RepositoryItem{
+		{Name: "org/a", PullRequestItems: []PullRequestItem{draft, approved, newPR("org/a", 1, "alice", 40)}},
+		{Name: "org/b", PullRequestItems: []PullRequestItem{newPR("org/b", 1, "carol", 5)}},
+	}
+
+	stats := computeStats(repositories, now)
+
+	if stats.Total != 4 {
+		t.Errorf("Total = %d, want 4", stats.Total)
+	}
+	if stats.Drafts != 1 || stats.DraftRatio != 0.25 {
+		t.Errorf("Drafts = %d, DraftRatio = %v, want 1, 0.25", stats.Drafts, stats.DraftRatio)
+	}
+	if stats.MedianAgeDays != 5 {
+		t.Errorf("MedianAgeDays = %v, want 5", stats.MedianAgeDays)
+	}
+	if stats.P90AgeDays != 40 {
+		t.Errorf("P90AgeDays = %v, want 40", stats.P90AgeDays)
+	}
+	if stats.Oldest == nil || stats.Oldest.Repository != "org/a" || stats.Oldest.Number != 1 {
+		t.Errorf("Oldest = %+v, want org/a#1", stats.Oldest)
+	}
+
+	wantRepositories := []countEntry{{"org/a", 3}, {"org/b", 1}}
+	if !reflect.DeepEqual(stats.Repositories, wantRepositories) {
+		t.Errorf("Repositories = %v, want %v", stats.Repositories, wantRepositories)
+	}
+	wantAuthors := []countEntry{{"alice", 2}, {"bob", 1}, {"carol", 1}}
+	if !reflect.DeepEqual(stats.Authors, wantAuthors) {
+		t.Errorf("Authors = %v, want %v", stats.Authors, wantAuthors)
+	}
+	wantCheckStatuses := []countEntry{{checkStatusSuccess, 3}, {checkStatusFailure, 1}}
+	if !reflect.DeepEqual(stats.CheckStatuses, wantCheckStatuses) {
+		t.Errorf("CheckStatuses = %v, want %v", stats.CheckStatuses, wantCheckStatuses)
+	}
+	wantReviewDecisions := []countEntry{{reviewDecisionNone, 3}, {reviewDecisionApproved, 1}}
+	if !reflect.DeepEqual(stats.ReviewDecisions, wantReviewDecisions) {
+		t.Errorf("ReviewDecisions = %v, want %v", stats.ReviewDecisions, wantReviewDecisions)
+	}
+}
+
+func TestComputeStatsEmpty(t *testing.T) {
+	stats := computeStats(nil, time.Now())
+
+	if stats.Total != 0 || stats.DraftRatio != 0 || stats.MedianAgeDays != 0 || stats.Oldest != nil {
+		t.Errorf("computeStats(nil) = %+v, want zero stats", stats)
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	values := []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	tests := []struct {
+		p    float64
+		want float64
+	}{
+		{p: 0, want: 1},
+		{p: 50, want: 5},
+		{p: 90, want: 9},
+		{p: 100, want: 10},
+	}
+
+	for _, tt := range tests {
+		if got := percentile(values, tt.p); got != tt.want {
+			t.Errorf("percentile(%v) = %v, want %v", tt.p, got, tt.want)
+		}
+	}
+}
+
+func TestWriteStats(t *testing.T) {
+	now := time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)
+	repositories := []RepositoryItem{
+		{Name: "org/a", PullRequestItems: []PullRequestItem{
+			{Number: 1, Title: "Fix bug", Author: "alice", RepositoryName: "org/a", CreatedAt: now.AddDate(0, 0, -3), CheckStatus: checkStatusSuccess},
+		}},
+	}
+	stats := computeStats(repositories, now)
+
+	var buf bytes.Buffer
+	if err := writeStats(&buf, stats); err != nil {
+		t.Fatalf("writeStats() returned error: %v", err)
+	}
+	wantContains := []string{
+		"PRs         1\n",
+		"Drafts      0 (0%)\n",
+		"Oldest      org/a#1 Fix bug (3d)\n",
+		"\nAuthor  PRs\nalice   1\n",
+	}
+	for _, want := range wantContains {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("writeStats() = %q, want to contain %q", buf.String(), want)
+		}
+	}
+
+	buf.Reset()
+	if err := writeStatsJSON(&buf, stats); err != nil {
+		t.Fatalf("writeStatsJSON() returned error: %v", err)
+	}
+	var decoded Stats
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("writeStatsJSON() wrote invalid JSON: %v", err)
+	}
+	if !reflect.DeepEqual(decoded, stats) {
+		t.Errorf("decoded JSON = %+v, want %+v", decoded, stats)
+	}
+}
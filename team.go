@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	graphql "github.com/cli/shurcooL-graphql"
+)
+
+type teamMembersQuery struct {
+	Organization struct {
+		Team *struct {
+			Members struct {
+				Nodes []struct {
+					Login string
+				}
+				PageInfo struct {
+					HasNextPage bool
+					EndCursor   string
+				}
+			} `graphql:"members(first: 100, after: $cursor)"`
+		} `graphql:"team(slug: $slug)"`
+	} `graphql:"organization(login: $org)"`
+}
+
+// fetchTeamMembers returns the logins of the members of team, written as
+// "[<host>/]<org>/<team-slug>". Members of child teams are included.
+func fetchTeamMembers(team string, defaultHost string) ([]string, error) {
+	host := defaultHost
+	parts := strings.Split(team, "/")
+	if len(parts) == 3 {
+		host, parts = parts[0], parts[1:]
+	}
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("invalid team: %s", team)
+	}
+
+	client, err := graphQLClient(host)
+	if err != nil {
+		return nil, err
+	}
+	return queryTeamMembers(client, parts[0], parts[1])
+}
+
+func queryTeamMembers(client *api.GraphQLClient, org string, slug string) ([]string, error) {
+	members := []string{}
+	var cursor *graphql.String
+	for {
+		var query = teamMembersQuery{}
+		variables := map[string]interface{}{
+			"org":    graphql.String(org),
+			"slug":   graphql.String(slug),
+			"cursor": cursor,
+		}
+		if err := client.Query("TeamMembers", &query, variables); err != nil {
+			return nil, err
+		}
+
+		team := query.Organization.Team
+		if team == nil {
+			return nil, fmt.Errorf("team not found: %s/%s", org, slug)
+		}
+		for _, node := range team.Members.Nodes {
+			members = append(members, node.Login)
+		}
+		if !team.Members.PageInfo.HasNextPage {
+			return members, nil
+		}
+		cursor = graphql.NewString(graphql.String(team.Members.PageInfo.EndCursor))
+	}
+}
+
+// maxAuthors is the most authors searched at once. Each search holds a
+// handful of them, so a large team takes many searches.
+const maxAuthors = 100
+
+// resolveAuthors returns the --author logins followed by the members of
+// the --team teams, without duplicates.
+func resolveAuthors(opts *Options) ([]string, error) {
+	authors := []string{}
+	seen := map[string]bool{}
+	add := func(logins []string) {
+		for _, login := range logins {
+			if !seen[strings.ToLower(login)] {
+				seen[strings.ToLower(login)] = true
+				authors = append(authors, login)
+			}
+		}
+	}
+
+	add(opts.Authors)
+	for _, team := range opts.Teams {
+		members, err := fetchTeamMembers(team, opts.Hostname)
+		if err != nil {
+			return nil, err
+		}
+		if len(members) == 0 {
+			return nil, fmt.Errorf("team has no members: %s", team)
+		}
+		add(members)
+	}
+	if len(authors) > maxAuthors {
+		return nil, fmt.Errorf("%d authors, at most %d can be searched at once", len(authors), maxAuthors)
+	}
+	return authors, nil
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+func TestQueryTeamMembers(t *testing.T) {
+	pages := map[string]string{
+		"":      `{"data": {"organization": {"team": {"members": {"nodes": [{"login": "alice"}, {"login": "bob"}], "pageInfo": {"hasNextPage": true, "endCursor": "c1"}}}}}}`,
+		"c1":    `{"data": {"organization": {"team": {"members": {"nodes": [{"login": "carol"}], "pageInfo": {"hasNextPage": false, "endCursor": "c2"}}}}}}`,
+		"other": `{"data": {"organization": {"team": null}}}`,
+	}
+	client, err := api.NewGraphQLClient(api.ClientOptions{
+		Host:      "github.com",
+		AuthToken: "token",
+		Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+			var request struct {
+				Variables map[string]interface{} `json:"variables"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+				return nil, err
+			}
+			page := ""
+			if cursor, ok := request.Variables["cursor"].(string); ok {
+				page = cursor
+			}
+			if request.Variables["slug"] == "other" {
+				page = "other"
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": []string{"application/json"}},
+				Body:       io.NopCloser(strings.NewReader(pages[page])),
+				Request:    r,
+			}, nil
+		}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	members, err := queryTeamMembers(client, "myorg", "platform")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"alice", "bob", "carol"}; !reflect.DeepEqual(members, want) {
+		t.Errorf("queryTeamMembers() = %v, want %v", members, want)
+	}
+
+	if _, err := queryTeamMembers(client, "myorg", "other"); err == nil {
+		t.Error("queryTeamMembers() of a missing team returned no error")
+	}
+}
+
+func TestResolveAuthors(t *testing.T) {
+	authors, err := resolveAuthors(&Options{Authors: []string{"alice", "bob", "Alice"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"alice", "bob"}; !reflect.DeepEqual(authors, want) {
+		t.Errorf("resolveAuthors() = %v, want %v", authors, want)
+	}
+
+	if _, err := resolveAuthors(&Options{Teams: []string{"platform"}}); err == nil {
+		t.Error("resolveAuthors() with a team without org returned no error")
+	}
+
+	many := []string{}
+	for i := 0; i <= maxAuthors; i++ {
+		many = append(many, fmt.Sprintf("member-%03d", i))
+	}
+	if _, err := resolveAuthors(&Options{Authors: many}); err == nil {
+		t.Errorf("resolveAuthors() with %d authors returned no error", len(many))
+	}
+}
+
+// fakeGraphQL answers the team members query with members and each search
+// with as many PRs as requested, authored by the first author of the
+// query, and records the searched query strings.
+func fakeGraphQL(t *testing.T, members []string, searched *[]string) {
+	t.Setenv("GH_TOKEN", "token")
+	t.Setenv("GH_HOST", "github.com")
+	t.Setenv("GH_CONFIG_DIR", t.TempDir())
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	graphQLTransport = roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		var request struct {
+			Query     string                 `json:"query"`
+			Variables map[string]interface{} `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			return nil, err
+		}
+
+		data := map[string]interface{}{}
+		if strings.Contains(request.Query, "members(") {
+			nodes := []map[string]string{}
+			for _, login := range members {
+				nodes = append(nodes, map[string]string{"login": login})
+			}
+			data["organization"] = map[string]interface{}{"team": map[string]interface{}{"members": map[string]interface{}{"nodes": nodes, "pageInfo": map[string]interface{}{"hasNextPage": false}}}}
+		} else {
+			first := int(request.Variables["first"].(float64))
+			for i := 0; ; i++ {
+				queryString, ok := request.Variables[fmt.Sprintf("query%d", i)].(string)
+				if !ok {
+					break
+				}
+				*searched = append(*searched, queryString)
+				author := strings.Fields(queryString[strings.Index(queryString, "author:")+len("author:"):])[0]
+				nodes := []map[string]interface{}{}
+				for n := 0; n < first; n++ {
+					number := len(*searched)*1000 + n
+					nodes = append(nodes, map[string]interface{}{
+						"number":     number,
+						"url":        fmt.Sprintf("https://github.com/myorg/repo/pull/%d", number),
+						"createdAt":  time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC).Add(time.Duration(number) * time.Minute),
+						"author":     map[string]string{"login": author},
+						"repository": map[string]string{"nameWithOwner": "myorg/repo", "url": "https://github.com/myorg/repo"},
+						"commits":    map[string]interface{}{"nodes": []interface{}{}},
+						"labels":     map[string]interface{}{"nodes": []interface{}{}},
+					})
+				}
+				data[fmt.Sprintf("search%d", i)] = map[string]interface{}{"issueCount": 1000, "pageInfo": map[string]interface{}{"hasNextPage": true, "endCursor": "next"}, "nodes": nodes}
+			}
+		}
+
+		body, err := json.Marshal(map[string]interface{}{"data": data})
+		if err != nil {
+			return nil, err
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       io.NopCloser(bytes.NewReader(body)),
+			Request:    r,
+		}, nil
+	})
+	t.Cleanup(func() { graphQLTransport = nil })
+}
+
+func TestFetchRepositoriesWithLargeTeam(t *testing.T) {
+	members := []string{}
+	for i := 0; i < 30; i++ {
+		members = append(members, fmt.Sprintf("member-%02d", i))
+	}
+	searched := []string{}
+	fakeGraphQL(t, members, &searched)
+
+	opts := &Options{Limit: 10, State: stateOpen, Teams: []string{"myorg/platform"}}
+	repositories, err := fetchRepositories([]string{"myorg"}, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(searched) < 2 {
+		t.Fatalf("searches = %q, want the authors split into several", searched)
+	}
+	pullRequests := allPullRequests(repositories)
+	if len(pullRequests) != opts.Limit {
+		t.Errorf("PRs = %d, want the limit %d in total, not per search", len(pullRequests), opts.Limit)
+	}
+	// The last search answered has the most recently created PRs, which
+	// are the ones kept.
+	lastSearch := searched[len(searched)-1]
+	for _, pr := range pullRequests {
+		if !strings.Contains(lastSearch, "author:"+pr.Author+" ") && !strings.HasSuffix(lastSearch, "author:"+pr.Author) {
+			t.Errorf("PR #%d by %s, want the newest PRs, from %q", pr.Number, pr.Author, lastSearch)
+		}
+	}
+}
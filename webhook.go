@@ -0,0 +1,186 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	webhookFormatSlack = "slack"
+	webhookFormatTeams = "teams"
+	webhookFormatJSON  = "json"
+)
+
+const (
+	// slackMaxBlocks leaves room for the header and the overflow note
+	// within the limit of 50 blocks per message.
+	slackMaxBlocks = 47
+	// slackMaxTextLength is the limit of a section text.
+	slackMaxTextLength = 3000
+)
+
+var slackTextReplacer = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+func digestSummary(repositories []RepositoryItem) string {
+	total := 0
+	failing := 0
+	for _, repo := range repositories {
+		for _, pr := range repo.PullRequestItems {
+			total++
+			if pr.CheckStatus == checkStatusFailure {
+				failing++
+			}
+		}
+	}
+	return fmt.Sprintf("%d PRs in %d repositories (%d failing)", total, len(repositories), failing)
+}
+
+type slackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type slackBlock struct {
+	Type string    `json:"type"`
+	Text slackText `json:"text"`
+}
+
+type slackMessage struct {
+	Text   string       `json:"text"`
+	Blocks []slackBlock `json:"blocks"`
+}
+
+func slackPayload(repositories []RepositoryItem) slackMessage {
+	summary := digestSummary(repositories)
+	message := slackMessage{
+		Text:   summary,
+		Blocks: []slackBlock{{Type: "header", Text: slackText{Type: "plain_text", Text: summary}}},
+	}
+
+	for i, repo := range repositories {
+		if i == slackMaxBlocks {
+			note := fmt.Sprintf("and %d more repositories", len(repositories)-i)
+			message.Blocks = append(message.Blocks, slackBlock{Type: "section", Text: slackText{Type: "mrkdwn", Text: note}})
+			break
+		}
+
+		text := fmt.Sprintf("*<%s|%s>*", repo.Url, slackTextReplacer.Replace(repo.Name))
+		for j, pr := range repo.PullRequestItems {
+			line := fmt.Sprintf("\n<%s|#%d> %s — %s %s%s", pr.Url, pr.Number, slackTextReplacer.Replace(pr.Title), pr.Author, markdownCheckStatus(&pr), markdownReviewDecision(&pr))
+			rest := fmt.Sprintf("\nand %d more", len(repo.PullRequestItems)-j)
+			if len(text)+len(line)+len(rest) > slackMaxTextLength {
+				text += rest
+				break
+			}
+			text += strings.TrimRight(line, " ")
+		}
+		message.Blocks = append(message.Blocks, slackBlock{Type: "section", Text: slackText{Type: "mrkdwn", Text: text}})
+	}
+	return message
+}
+
+type teamsTextBlock struct {
+	Type   string `json:"type"`
+	Text   string `json:"text"`
+	Wrap   bool   `json:"wrap"`
+	Size   string `json:"size,omitempty"`
+	Weight string `json:"weight,omitempty"`
+}
+
+type teamsCard struct {
+	Schema  string           `json:"$schema"`
+	Type    string           `json:"type"`
+	Version string           `json:"version"`
+	Body    []teamsTextBlock `json:"body"`
+}
+
+type teamsAttachment struct {
+	ContentType string    `json:"contentType"`
+	Content     teamsCard `json:"content"`
+}
+
+type teamsMessage struct {
+	Type        string            `json:"type"`
+	Attachments []teamsAttachment `json:"attachments"`
+}
+
+func teamsPayload(repositories []RepositoryItem) teamsMessage {
+	card := teamsCard{
+		Schema:  "http://adaptivecards.io/schemas/adaptive-card.json",
+		Type:    "AdaptiveCard",
+		Version: "1.4",
+		Body:    []teamsTextBlock{{Type: "TextBlock", Text: digestSummary(repositories), Wrap: true, Size: "Large", Weight: "Bolder"}},
+	}
+	for _, repo := range repositories {
+		card.Body = append(card.Body, teamsTextBlock{Type: "TextBlock", Text: markdownLink(repo.Name, repo.Url), Wrap: true, Weight: "Bolder"})
+		lines := make([]string, 0, len(repo.PullRequestItems))
+		for _, pr := range repo.PullRequestItems {
+			line := fmt.Sprintf("- %s %s — %s %s%s", markdownLink(fmt.Sprintf("#%d", pr.Number), pr.Url), pr.Title, pr.Author, markdownCheckStatus(&pr), markdownReviewDecision(&pr))
+			lines = append(lines, strings.TrimRight(line, " "))
+		}
+		card.Body = append(card.Body, teamsTextBlock{Type: "TextBlock", Text: strings.Join(lines, "\n"), Wrap: true})
+	}
+
+	return teamsMessage{
+		Type:        "message",
+		Attachments: []teamsAttachment{{ContentType: "application/vnd.microsoft.card.adaptive", Content: card}},
+	}
+}
+
+type jsonDigest struct {
+	GeneratedAt  time.Time        `json:"generated_at"`
+	Summary      string           `json:"summary"`
+	Repositories []RepositoryItem `json:"repositories"`
+}
+
+// webhookPayload builds the digest of repositories in format.
+func webhookPayload(format string, repositories []RepositoryItem, now time.Time) (interface{}, error) {
+	switch format {
+	case webhookFormatSlack:
+		return slackPayload(repositories), nil
+	case webhookFormatTeams:
+		return teamsPayload(repositories), nil
+	case webhookFormatJSON:
+		if repositories == nil {
+			repositories = []RepositoryItem{}
+		}
+		return jsonDigest{GeneratedAt: now, Summary: digestSummary(repositories), Repositories: repositories}, nil
+	default:
+		return nil, fmt.Errorf("invalid webhook format: %s", format)
+	}
+}
+
+// postWebhook posts payload as JSON to url.
+func postWebhook(url string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("webhook returned %s: %s", resp.Status, strings.TrimSpace(string(message)))
+	}
+	return nil
+}
+
+// notifyWebhook posts the digest of repositories in format to url.
+func notifyWebhook(url string, format string, repositories []RepositoryItem) error {
+	payload, err := webhookPayload(format, repositories, time.Now())
+	if err != nil {
+		return err
+	}
+	return postWebhook(url, payload)
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func webhookTestRepositories() []RepositoryItem {
+	return []RepositoryItem{
+		{
+			Name: "test/repo",
+			Url:  "https://github.com/test/repo",
+			PullRequestItems: []PullRequestItem{
+				{Number: 2, Title: "Fix <b> & co", Author: "alice", Url: "https://github.com/test/repo/pull/2", RepositoryName: "test/repo", CheckStatus: checkStatusFailure},
+				{Number: 1, Title: "Add feature", Author: "bob", Url: "https://github.com/test/repo/pull/1", RepositoryName: "test/repo", CheckStatus: checkStatusSuccess, ReviewDecision: reviewDecisionApproved},
+			},
+		},
+	}
+}
+
+func TestDigestSummary(t *testing.T) {
+	expected := "2 PRs in 1 repositories (1 failing)"
+	result := digestSummary(webhookTestRepositories())
+
+	if result != expected {
+		t.Errorf("digestSummary() = %q, want %q", result, expected)
+	}
+}
+
+func TestSlackPayload(t *testing.T) {
+	message := slackPayload(webhookTestRepositories())
+
+	if len(message.Blocks) != 2 {
+		t.Fatalf("len(Blocks) = %d, want 2", len(message.Blocks))
+	}
+	if message.Blocks[0].Type != "header" {
+		t.Errorf("Blocks[0].Type = %q, want header", message.Blocks[0].Type)
+	}
+
+	text := message.Blocks[1].Text.Text
+	wantContains := []string{
+		"*<https://github.com/test/repo|test/repo>*",
+		"<https://github.com/test/repo/pull/2|#2> Fix &lt;b&gt; &amp; co — alice ❌",
+		"<https://github.com/test/repo/pull/1|#1> Add feature — bob ✅👍",
+	}
+	for _, want := range wantContains {
+		if !strings.Contains(text, want) {
+			t.Errorf("section text = %q, want to contain %q", text, want)
+		}
+	}
+}
+
+func TestSlackPayloadLimits(t *testing.T) {
+	items := []PullRequestItem{}
+	for i := 0; i < 200; i++ {
+		items = append(items, PullRequestItem{Number: i, Title: strings.Repeat("x", 50), Url: "https://github.com/test/repo/pull/1"})
+	}
+	repositories := []RepositoryItem{}
+	for i := 0; i < 60; i++ {
+		repositories = append(repositories, RepositoryItem{Name: "test/repo", PullRequestItems: items})
+	}
+
+	message := slackPayload(repositories)
+
+	if len(message.Blocks) > 50 {
+		t.Errorf("len(Blocks) = %d, want at most 50", len(message.Blocks))
+	}
+	for _, block := range message.Blocks {
+		if len(block.Text.Text) > slackMaxTextLength {
+			t.Errorf("len(text) = %d, want at most %d", len(block.Text.Text), slackMaxTextLength)
+		}
+	}
+	if last := message.Blocks[len(message.Blocks)-1].Text.Text; last != "and 13 more repositories" {
+		t.Errorf("last block = %q, want %q", last, "and 13 more repositories")
+	}
+}
+
+func TestTeamsPayload(t *testing.T) {
+	message := teamsPayload(webhookTestRepositories())
+
+	if len(message.Attachments) != 1 {
+		t.Fatalf("len(Attachments) = %d, want 1", len(message.Attachments))
+	}
+	body := message.Attachments[0].Content.Body
+	if len(body) != 3 {
+		t.Fatalf("len(Body) = %d, want 3", len(body))
+	}
+	if body[1].Text != "[test/repo](https://github.com/test/repo)" {
+		t.Errorf("Body[1].Text = %q", body[1].Text)
+	}
+	if !strings.Contains(body[2].Text, "- [#1](https://github.com/test/repo/pull/1) Add feature — bob ✅👍") {
+		t.Errorf("Body[2].Text = %q", body[2].Text)
+	}
+}
+
+func TestWebhookPayloadInvalidFormat(t *testing.T) {
+	if _, err := webhookPayload("xml", nil, time.Now()); err == nil {
+		t.Error("webhookPayload() returned no error")
+	}
+}
+
+func TestNotifyWebhook(t *testing.T) {
+	var gotContentType string
+	var gotBody []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotContentType = r.Header.Get("Content-Type")
+		gotBody, _ = io.ReadAll(r.Body)
+	}))
+	defer server.Close()
+
+	if err := notifyWebhook(server.URL, webhookFormatJSON, webhookTestRepositories()); err != nil {
+		t.Fatalf("notifyWebhook() returned error: %v", err)
+	}
+
+	if gotContentType != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", gotContentType)
+	}
+	var digest jsonDigest
+	if err := json.Unmarshal(gotBody, &digest); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if digest.Summary != "2 PRs in 1 repositories (1 failing)" || len(digest.Repositories) != 1 {
+		t.Errorf("digest = %+v", digest)
+	}
+}
+
+func TestNotifyWebhookError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "invalid_payload", http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	err := notifyWebhook(server.URL, webhookFormatSlack, webhookTestRepositories())
+	if err == nil || !strings.Contains(err.Error(), "invalid_payload") {
+		t.Errorf("notifyWebhook() error = %v, want error containing the response", err)
+	}
+}